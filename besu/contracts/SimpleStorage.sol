@@ -4,8 +4,12 @@ pragma solidity >=0.4.16 <0.9.0;
 contract SimpleStorage {
     uint storedData;
 
+    event ValueChanged(address indexed setter, uint256 oldValue, uint256 newValue);
+
     function set(uint x) public {
+        uint oldValue = storedData;
         storedData = x;
+        emit ValueChanged(msg.sender, oldValue, x);
     }
 
     function get() public view returns (uint) {
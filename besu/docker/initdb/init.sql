@@ -1,6 +1,3 @@
-CREATE TABLE contract_values (
-                                 id SERIAL PRIMARY KEY,
-                                 contract_key TEXT UNIQUE NOT NULL,
-                                 contract_value TEXT NOT NULL,
-                                 created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
+-- O schema do banco é versionado pelas migrações embutidas na aplicação Go
+-- (go-app/internal/database/migrations). Elas são aplicadas na inicialização
+-- da API (DB_AUTO_MIGRATE=true) ou manualmente com `go run . migrate up`.
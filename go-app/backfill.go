@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmm2136/besu_challenge/go-app/internal/config"
+	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
+	"github.com/vmm2136/besu_challenge/go-app/internal/database"
+	"github.com/vmm2136/besu_challenge/go-app/internal/service"
+)
+
+// runBackfillCommand executa o subcomando "backfill": reconstrói o histórico dos contratos a partir dos eventos
+// já emitidos na rede, do bloco de implantação (ou -from) até o último bloco confirmado (ou -to).
+// Uso: backfill [-contract Nome] [-from N] [-to N] [-batch N] [-workers N]
+func runBackfillCommand(ctx context.Context, cfg *config.Config, registry *contract.Registry, dbClient database.DBClient, args []string) error {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	contractName := flags.String("contract", "", "contrato a preencher (padrão: todos com o evento ValueChanged)")
+	fromBlock := flags.Int64("from", -1, "primeiro bloco (padrão: bloco de implantação do contrato)")
+	toBlock := flags.Int64("to", -1, "último bloco (padrão: último bloco com SYNC_CONFIRMATIONS confirmações)")
+	batchSize := flags.Uint64("batch", cfg.BackfillBatchSize, "blocos por lote")
+	workers := flags.Int("workers", cfg.BackfillWorkers, "lotes lidos em paralelo")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	names := registry.Names()
+	if *contractName != "" {
+		names = []string{*contractName}
+	}
+
+	for _, name := range names {
+		contractClient, ok := registry.Get(name)
+		if !ok {
+			return fmt.Errorf("contrato '%s' não encontrado", name)
+		}
+		if !contractClient.SupportsValueChanged() {
+			if *contractName != "" {
+				return fmt.Errorf("contrato '%s' não declara o evento '%s'", name, contract.ValueChangedEventName)
+			}
+			continue
+		}
+
+		backfiller, err := service.NewBackfiller(contractClient, dbClient, cfg.IndexerStartBlock, *batchSize, *workers)
+		if err != nil {
+			return err
+		}
+
+		from := uint64(*fromBlock)
+		if *fromBlock < 0 {
+			from, err = contractClient.DeploymentBlock(ctx)
+			if err != nil {
+				fmt.Printf("Aviso: não foi possível encontrar o bloco de implantação de %s (%v); usando INDEXER_START_BLOCK=%d\n", name, err, cfg.IndexerStartBlock)
+				from = cfg.IndexerStartBlock
+			}
+		}
+
+		to := uint64(*toBlock)
+		if *toBlock < 0 {
+			head, err := contractClient.LatestBlockNumber(ctx)
+			if err != nil {
+				return err
+			}
+			if head < cfg.SyncConfirmations {
+				fmt.Printf("%s: nenhum bloco confirmado para preencher.\n", name)
+				continue
+			}
+			to = head - cfg.SyncConfirmations
+		}
+
+		fmt.Printf("%s: preenchendo histórico dos blocos %d a %d (lotes de %d blocos, %d workers)\n", name, from, to, *batchSize, *workers)
+		report, err := backfiller.Run(ctx, from, to)
+		if err != nil {
+			return fmt.Errorf("erro no backfill de %s (execute novamente para continuar do checkpoint): %w", name, err)
+		}
+		switch {
+		case report.Batches == 0:
+			fmt.Printf("%s: histórico já preenchido até o bloco %d.\n", name, to)
+		case report.Resumed:
+			fmt.Printf("%s: retomado do bloco %d; %d lote(s) e %d evento(s) gravados.\n", name, report.FromBlock, report.Batches, report.Events)
+		default:
+			fmt.Printf("%s: %d lote(s) e %d evento(s) gravados.\n", name, report.Batches, report.Events)
+		}
+	}
+	return nil
+}
@@ -7,7 +7,10 @@ toolchain go1.23.11
 require (
 	github.com/ethereum/go-ethereum v1.16.1
 	github.com/go-chi/chi/v5 v5.2.2
+	github.com/joho/godotenv v1.5.1
 	github.com/lib/pq v1.10.9
+	github.com/tyler-smith/go-bip39 v1.1.0
+	golang.org/x/text v0.23.0
 )
 
 require (
@@ -26,7 +29,6 @@ require (
 	github.com/google/uuid v1.3.0 // indirect
 	github.com/gorilla/websocket v1.4.2 // indirect
 	github.com/holiman/uint256 v1.3.2 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
 	github.com/klauspost/cpuid/v2 v2.2.7 // indirect
 	github.com/shirou/gopsutil v3.21.4-0.20210419000835-c7a38de76ee5+incompatible // indirect
 	github.com/supranational/blst v0.3.14 // indirect
@@ -35,5 +37,4 @@ require (
 	golang.org/x/crypto v0.36.0 // indirect
 	golang.org/x/sync v0.12.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
-
 )
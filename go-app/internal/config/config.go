@@ -2,30 +2,304 @@ package config
 
 import (
 	"fmt"
+	"math/big"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmm2136/besu_challenge/go-app/internal/pkg/ethutils"
 )
 
 // Config armazena as configurações da aplicação
 type Config struct {
-	BesuNodeURL           string
-	ContractABIPath       string
+	BesuNodeURLs          []string
+	RPCHealthInterval     time.Duration
+	RPCMaxBlockLag        uint64
+	RPCMinPeers           uint64
+	RPCHeadPollInterval   time.Duration
+	RPCReconnectBackoff   time.Duration
+	SignerType            string
+	SignerKeystorePaths   []string
+	SignerPassphraseFile  string
+	SignerMnemonicFile    string
+	SignerDerivationPath  string
+	SignerAccountCount    uint64
+	SignerRemoteURL       string
+	SignerRemoteAddresses []string
+	TransactorStrategy    string
+	TransactorMinBalance  *big.Int
+	TransactorBalanceTTL  time.Duration
+	ContractArtifactsDir  string
 	ContractAddressesPath string
+	DefaultContract       string
 	ServerPort            string
 	DatabaseURL           string
+	DBAutoMigrate         bool
+	TxConfirmations       uint64
+	TxDropTimeout         time.Duration
+	TxPollInterval        time.Duration
+	TxPriceBumpPercent    uint64
+	IdempotencyKeyTTL     time.Duration
+	IdempotencyStaleAfter time.Duration
+	RawTxAllowedMethods   []string
+	SigningEnabled        bool
+	SigningAPIToken       string
+	SyncEnabled           bool
+	SyncMode              string
+	SyncInterval          time.Duration
+	SyncMaxBackoff        time.Duration
+	SyncConfirmations     uint64
+	IndexerEnabled        bool
+	IndexerStartBlock     uint64
+	IndexerBatchSize      uint64
+	IndexerInterval       time.Duration
+	BackfillBatchSize     uint64
+	BackfillWorkers       int
+	FeeStrategy           string
+	FeeFixedGasPrice      *big.Int
+	FeeFixedTipCap        *big.Int
+	FeePercentile         float64
+	FeeHistoryBlocks      uint64
+	FeeMaxFeeCap          *big.Int
+	FeeMaxTipCap          *big.Int
+	GasLimitMultiplier    float64
+	GasLimitCeiling       uint64
 }
 
 // LoadConfig carrega as configurações de variáveis de ambiente ou valores padrão
 func LoadConfig() (*Config, error) {
+	dbAutoMigrate, err := getEnvBoolOrDefault("DB_AUTO_MIGRATE", true)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcHealthInterval, err := getEnvDurationOrDefault("RPC_HEALTH_INTERVAL", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcMaxBlockLag, err := getEnvUintOrDefault("RPC_MAX_BLOCK_LAG", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcMinPeers, err := getEnvUintOrDefault("RPC_MIN_PEERS", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcHeadPollInterval, err := getEnvDurationOrDefault("RPC_HEAD_POLL_INTERVAL", time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcReconnectBackoff, err := getEnvDurationOrDefault("RPC_RECONNECT_MAX_BACKOFF", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	signerAccountCount, err := getEnvUintOrDefault("SIGNER_ACCOUNT_COUNT", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	transactorMinBalance, err := getEnvWeiOrDefault("TRANSACTOR_MIN_BALANCE", big.NewInt(0))
+	if err != nil {
+		return nil, err
+	}
+
+	transactorBalanceTTL, err := getEnvDurationOrDefault("TRANSACTOR_BALANCE_TTL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	txConfirmations, err := getEnvUintOrDefault("TX_CONFIRMATIONS", 2)
+	if err != nil {
+		return nil, err
+	}
+
+	txDropTimeout, err := getEnvDurationOrDefault("TX_DROP_TIMEOUT", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	txPollInterval, err := getEnvDurationOrDefault("TX_POLL_INTERVAL", time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	txPriceBumpPercent, err := getEnvUintOrDefault("TX_PRICE_BUMP_PERCENT", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	syncEnabled, err := getEnvBoolOrDefault("SYNC_ENABLED", true)
+	if err != nil {
+		return nil, err
+	}
+
+	syncInterval, err := getEnvDurationOrDefault("SYNC_INTERVAL", 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	syncMaxBackoff, err := getEnvDurationOrDefault("SYNC_MAX_BACKOFF", 2*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	syncConfirmations, err := getEnvUintOrDefault("SYNC_CONFIRMATIONS", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	indexerEnabled, err := getEnvBoolOrDefault("INDEXER_ENABLED", true)
+	if err != nil {
+		return nil, err
+	}
+
+	indexerStartBlock, err := getEnvUintOrDefault("INDEXER_START_BLOCK", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	indexerBatchSize, err := getEnvUintOrDefault("INDEXER_BATCH_SIZE", 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	indexerInterval, err := getEnvDurationOrDefault("INDEXER_INTERVAL", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	backfillBatchSize, err := getEnvUintOrDefault("BACKFILL_BATCH_SIZE", 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	backfillWorkers, err := getEnvUintOrDefault("BACKFILL_WORKERS", 4)
+	if err != nil {
+		return nil, err
+	}
+
+	feeFixedGasPrice, err := getEnvWeiOrDefault("FEE_FIXED_GAS_PRICE", big.NewInt(0))
+	if err != nil {
+		return nil, err
+	}
+
+	feeFixedTipCap, err := getEnvWeiOrDefault("FEE_FIXED_TIP_CAP", big.NewInt(0))
+	if err != nil {
+		return nil, err
+	}
+
+	feePercentile, err := getEnvFloatOrDefault("FEE_PERCENTILE", 50)
+	if err != nil {
+		return nil, err
+	}
+
+	feeHistoryBlocks, err := getEnvUintOrDefault("FEE_HISTORY_BLOCKS", 20)
+	if err != nil {
+		return nil, err
+	}
+
+	feeMaxFeeCap, err := getEnvWeiOrDefault("FEE_MAX_FEE_CAP", big.NewInt(0))
+	if err != nil {
+		return nil, err
+	}
+
+	feeMaxTipCap, err := getEnvWeiOrDefault("FEE_MAX_TIP_CAP", big.NewInt(0))
+	if err != nil {
+		return nil, err
+	}
+
+	idempotencyKeyTTL, err := getEnvDurationOrDefault("IDEMPOTENCY_KEY_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	signingEnabled, err := getEnvBoolOrDefault("SIGNING_ENABLED", false)
+	if err != nil {
+		return nil, err
+	}
+
+	idempotencyStaleAfter, err := getEnvDurationOrDefault("IDEMPOTENCY_STALE_AFTER", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimitMultiplier, err := getEnvFloatOrDefault("GAS_LIMIT_MULTIPLIER", 1.2)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimitCeiling, err := getEnvUintOrDefault("GAS_LIMIT_CEILING", 0)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
-		BesuNodeURL:           getEnvOrDefault("BESU_NODE_URL", "http://localhost:8545"),
-		ContractABIPath:       getEnvOrDefault("CONTRACT_ABI_PATH", "../besu/artifacts/contracts/SimpleStorage.sol/SimpleStorage.json"),
+		BesuNodeURLs:          getEnvListOrDefault("BESU_NODE_URLS", getEnvOrDefault("BESU_NODE_URL", "http://localhost:8545")),
+		RPCHealthInterval:     rpcHealthInterval,
+		RPCMaxBlockLag:        rpcMaxBlockLag,
+		RPCMinPeers:           rpcMinPeers,
+		RPCHeadPollInterval:   rpcHeadPollInterval,
+		RPCReconnectBackoff:   rpcReconnectBackoff,
+		SignerType:            getEnvOrDefault("SIGNER_TYPE", "env"),
+		SignerKeystorePaths:   getEnvListOrDefault("SIGNER_KEYSTORE_PATH", ""),
+		SignerPassphraseFile:  getEnvOrDefault("SIGNER_PASSPHRASE_FILE", ""),
+		SignerMnemonicFile:    getEnvOrDefault("SIGNER_MNEMONIC_FILE", ""),
+		SignerDerivationPath:  getEnvOrDefault("SIGNER_DERIVATION_PATH", ethutils.DefaultDerivationPath),
+		SignerAccountCount:    signerAccountCount,
+		SignerRemoteURL:       getEnvOrDefault("SIGNER_REMOTE_URL", ""),
+		SignerRemoteAddresses: getEnvListOrDefault("SIGNER_REMOTE_ADDRESS", ""),
+		TransactorStrategy:    getEnvOrDefault("TRANSACTOR_STRATEGY", "round-robin"),
+		TransactorMinBalance:  transactorMinBalance,
+		TransactorBalanceTTL:  transactorBalanceTTL,
+		ContractArtifactsDir:  getEnvOrDefault("CONTRACT_ARTIFACTS_DIR", "../besu/artifacts/contracts"),
 		ContractAddressesPath: getEnvOrDefault("CONTRACT_ADDRESSES_PATH", "../besu/ignition/deployments/chain-1337/deployed_addresses.json"),
+		DefaultContract:       getEnvOrDefault("DEFAULT_CONTRACT", "SimpleStorage"),
 		ServerPort:            getEnvOrDefault("SERVER_PORT", "8080"),
 		DatabaseURL:           getEnvOrDefault("DATABASE_URL", "root:root@tcp(127.0.0.1:3306)/besu_db?parseTime=true"),
+		DBAutoMigrate:         dbAutoMigrate,
+		TxConfirmations:       txConfirmations,
+		TxDropTimeout:         txDropTimeout,
+		TxPollInterval:        txPollInterval,
+		TxPriceBumpPercent:    txPriceBumpPercent,
+		IdempotencyKeyTTL:     idempotencyKeyTTL,
+		IdempotencyStaleAfter: idempotencyStaleAfter,
+		RawTxAllowedMethods:   getEnvListOrDefault("RAW_TX_ALLOWED_METHODS", "set"),
+		SigningEnabled:        signingEnabled,
+		SigningAPIToken:       getEnvOrDefault("SIGNING_API_TOKEN", ""),
+		SyncEnabled:           syncEnabled,
+		SyncMode:              getEnvOrDefault("SYNC_MODE", "interval"),
+		SyncInterval:          syncInterval,
+		SyncMaxBackoff:        syncMaxBackoff,
+		SyncConfirmations:     syncConfirmations,
+		IndexerEnabled:        indexerEnabled,
+		IndexerStartBlock:     indexerStartBlock,
+		IndexerBatchSize:      indexerBatchSize,
+		IndexerInterval:       indexerInterval,
+		BackfillBatchSize:     backfillBatchSize,
+		BackfillWorkers:       int(backfillWorkers),
+		FeeStrategy:           getEnvOrDefault("FEE_STRATEGY", "suggested"),
+		FeeFixedGasPrice:      feeFixedGasPrice,
+		FeeFixedTipCap:        feeFixedTipCap,
+		FeePercentile:         feePercentile,
+		FeeHistoryBlocks:      feeHistoryBlocks,
+		FeeMaxFeeCap:          feeMaxFeeCap,
+		FeeMaxTipCap:          feeMaxTipCap,
+		GasLimitMultiplier:    gasLimitMultiplier,
+		GasLimitCeiling:       gasLimitCeiling,
+	}
+
+	if len(cfg.BesuNodeURLs) == 0 {
+		return nil, fmt.Errorf("BESU_NODE_URLS (ou BESU_NODE_URL) não pode ser vazio")
 	}
 
-	if cfg.BesuNodeURL == "" {
-		return nil, fmt.Errorf("BESU_NODE_URL não pode ser vazio")
+	if cfg.SigningEnabled && cfg.SigningAPIToken == "" {
+		return nil, fmt.Errorf("SIGNING_API_TOKEN é obrigatório com SIGNING_ENABLED=true")
 	}
 
 	return cfg, nil
@@ -37,3 +311,75 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvListOrDefault lê uma lista separada por vírgulas, ignorando itens vazios
+func getEnvListOrDefault(key, defaultValue string) []string {
+	var items []string
+	for _, item := range strings.Split(getEnvOrDefault(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getEnvUintOrDefault(key string, defaultValue uint64) (uint64, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("valor inválido para %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func getEnvBoolOrDefault(key string, defaultValue bool) (bool, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("valor inválido para %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func getEnvDurationOrDefault(key string, defaultValue time.Duration) (time.Duration, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("valor inválido para %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func getEnvFloatOrDefault(key string, defaultValue float64) (float64, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("valor inválido para %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// getEnvWeiOrDefault lê um valor em wei (decimal ou hexadecimal com 0x)
+func getEnvWeiOrDefault(key string, defaultValue *big.Int) (*big.Int, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := ethutils.ParseUint256(value)
+	if err != nil {
+		return nil, fmt.Errorf("valor inválido para %s: %w", key, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,292 @@
+package contract
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MethodOutput é um valor retornado por uma função do contrato, já convertido para JSON
+type MethodOutput struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// decodeArguments converte os argumentos recebidos em JSON para os tipos Go esperados pelo ABI
+func decodeArguments(inputs abi.Arguments, raw []json.RawMessage) ([]interface{}, error) {
+	if len(raw) != len(inputs) {
+		return nil, fmt.Errorf("%w: esperados %d argumentos, recebidos %d", ErrInvalidArguments, len(inputs), len(raw))
+	}
+
+	args := make([]interface{}, len(inputs))
+	for i, input := range inputs {
+		value, err := decodeValue(input.Type, raw[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: argumento %d (%s %s): %v", ErrInvalidArguments, i, input.Type.String(), input.Name, err)
+		}
+		args[i] = value.Interface()
+	}
+	return args, nil
+}
+
+// decodeValue converte um valor JSON para o tipo Go correspondente ao tipo ABI.
+// Inteiros aceitam número JSON ou string decimal/hexadecimal (0x); bytes e endereços são strings hexadecimais;
+// arrays são listas JSON e tuplas aceitam objeto (pelos nomes dos campos) ou lista (pela posição).
+func decodeValue(t abi.Type, raw json.RawMessage) (reflect.Value, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := decodeInteger(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return integerValue(t, n)
+
+	case abi.BoolTy:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return reflect.Value{}, fmt.Errorf("esperado booleano")
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.StringTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return reflect.Value{}, fmt.Errorf("esperada string")
+		}
+		return reflect.ValueOf(s), nil
+
+	case abi.AddressTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil || !common.IsHexAddress(s) {
+			return reflect.Value{}, fmt.Errorf("esperado endereço hexadecimal")
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+
+	case abi.BytesTy:
+		b, err := decodeHexBytes(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.FixedBytesTy:
+		b, err := decodeHexBytes(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("esperados %d bytes, recebidos %d", t.Size, len(b))
+		}
+		array := reflect.New(t.GetType()).Elem()
+		reflect.Copy(array, reflect.ValueOf(b))
+		return array, nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return reflect.Value{}, fmt.Errorf("esperada lista")
+		}
+
+		var list reflect.Value
+		if t.T == abi.ArrayTy {
+			if len(items) != t.Size {
+				return reflect.Value{}, fmt.Errorf("esperados %d itens, recebidos %d", t.Size, len(items))
+			}
+			list = reflect.New(t.GetType()).Elem()
+		} else {
+			list = reflect.MakeSlice(t.GetType(), len(items), len(items))
+		}
+
+		for i, item := range items {
+			value, err := decodeValue(*t.Elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("item %d: %v", i, err)
+			}
+			list.Index(i).Set(value)
+		}
+		return list, nil
+
+	case abi.TupleTy:
+		fields, err := tupleFields(t, raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		tuple := reflect.New(t.TupleType).Elem()
+		for i, elem := range t.TupleElems {
+			value, err := decodeValue(*elem, fields[i])
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("campo '%s': %v", t.TupleRawNames[i], err)
+			}
+			tuple.Field(i).Set(value)
+		}
+		return tuple, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("tipo ABI %s não suportado", t.String())
+}
+
+// tupleFields separa os campos de uma tupla recebida como objeto JSON (por nome) ou lista (por posição)
+func tupleFields(t abi.Type, raw json.RawMessage) ([]json.RawMessage, error) {
+	var positional []json.RawMessage
+	if err := json.Unmarshal(raw, &positional); err == nil {
+		if len(positional) != len(t.TupleElems) {
+			return nil, fmt.Errorf("esperados %d campos, recebidos %d", len(t.TupleElems), len(positional))
+		}
+		return positional, nil
+	}
+
+	var named map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &named); err != nil {
+		return nil, fmt.Errorf("esperado objeto ou lista")
+	}
+
+	fields := make([]json.RawMessage, len(t.TupleElems))
+	for i, name := range t.TupleRawNames {
+		field, ok := named[name]
+		if !ok {
+			return nil, fmt.Errorf("campo '%s' ausente", name)
+		}
+		fields[i] = field
+	}
+	return fields, nil
+}
+
+func decodeInteger(raw json.RawMessage) (*big.Int, error) {
+	text := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, fmt.Errorf("esperado inteiro")
+		}
+		text = strings.TrimSpace(text)
+	}
+
+	negative := strings.HasPrefix(text, "-")
+	digits := strings.TrimPrefix(text, "-")
+	base := 10
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		digits, base = digits[2:], 16
+	}
+
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok || digits == "" || strings.HasPrefix(digits, "+") || strings.HasPrefix(digits, "-") {
+		return nil, fmt.Errorf("'%s' não é um inteiro válido", text)
+	}
+	if negative {
+		n.Neg(n)
+	}
+	return n, nil
+}
+
+// integerValue verifica se o inteiro cabe no tipo ABI e o converte para o tipo Go usado pelo go-ethereum
+// (int8..int64/uint8..uint64 para tamanhos nativos e *big.Int para os demais)
+func integerValue(t abi.Type, n *big.Int) (reflect.Value, error) {
+	min, max := new(big.Int), new(big.Int).Lsh(big.NewInt(1), uint(t.Size))
+	if t.T == abi.IntTy {
+		max.Rsh(max, 1)
+		min.Neg(max)
+	}
+	max.Sub(max, big.NewInt(1))
+	if n.Cmp(min) < 0 || n.Cmp(max) > 0 {
+		return reflect.Value{}, fmt.Errorf("%s fora do intervalo [%s, %s]", n.String(), min.String(), max.String())
+	}
+
+	goType := t.GetType()
+	if goType == reflect.TypeOf((*big.Int)(nil)) {
+		return reflect.ValueOf(n), nil
+	}
+
+	value := reflect.New(goType).Elem()
+	if t.T == abi.IntTy {
+		value.SetInt(n.Int64())
+	} else {
+		value.SetUint(n.Uint64())
+	}
+	return value, nil
+}
+
+func decodeHexBytes(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("esperada string hexadecimal")
+	}
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return nil, fmt.Errorf("bytes devem ser enviados em hexadecimal com prefixo 0x")
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, fmt.Errorf("hexadecimal inválido: %v", err)
+	}
+	return b, nil
+}
+
+// encodeOutputs converte os valores retornados por uma função para tipos serializáveis em JSON
+func encodeOutputs(outputs abi.Arguments, values []interface{}) []MethodOutput {
+	result := make([]MethodOutput, 0, len(values))
+	for i, value := range values {
+		if i >= len(outputs) {
+			break
+		}
+		result = append(result, MethodOutput{
+			Name:  outputs[i].Name,
+			Type:  outputs[i].Type.String(),
+			Value: encodeValue(outputs[i].Type, reflect.ValueOf(value)),
+		})
+	}
+	return result
+}
+
+// encodeValue é o inverso de decodeValue: inteiros viram strings decimais (para não perder precisão),
+// bytes e endereços viram strings hexadecimais, arrays viram listas e tuplas viram objetos.
+func encodeValue(t abi.Type, value reflect.Value) interface{} {
+	if value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr && t.T != abi.IntTy && t.T != abi.UintTy {
+		value = value.Elem()
+	}
+
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		switch v := value.Interface().(type) {
+		case *big.Int:
+			return v.String()
+		default:
+			return fmt.Sprint(v)
+		}
+
+	case abi.AddressTy:
+		if address, ok := value.Interface().(common.Address); ok {
+			return address.Hex()
+		}
+
+	case abi.BytesTy, abi.FixedBytesTy, abi.HashTy:
+		b := make([]byte, value.Len())
+		reflect.Copy(reflect.ValueOf(b), value)
+		return "0x" + hex.EncodeToString(b)
+
+	case abi.SliceTy, abi.ArrayTy:
+		list := make([]interface{}, value.Len())
+		for i := range list {
+			list[i] = encodeValue(*t.Elem, value.Index(i))
+		}
+		return list
+
+	case abi.TupleTy:
+		tuple := make(map[string]interface{}, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			name := t.TupleRawNames[i]
+			if name == "" {
+				name = fmt.Sprintf("%d", i)
+			}
+			tuple[name] = encodeValue(*elem, value.Field(i))
+		}
+		return tuple
+	}
+
+	return value.Interface()
+}
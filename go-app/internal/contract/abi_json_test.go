@@ -0,0 +1,101 @@
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+const argumentsTestABI = `[
+	{"type":"function","name":"u8","inputs":[{"name":"v","type":"uint8"}]},
+	{"type":"function","name":"i8","inputs":[{"name":"v","type":"int8"}]},
+	{"type":"function","name":"u256","inputs":[{"name":"v","type":"uint256"}]},
+	{"type":"function","name":"i256","inputs":[{"name":"v","type":"int256"}]},
+	{"type":"function","name":"flag","inputs":[{"name":"v","type":"bool"}]},
+	{"type":"function","name":"text","inputs":[{"name":"v","type":"string"}]},
+	{"type":"function","name":"addr","inputs":[{"name":"v","type":"address"}]},
+	{"type":"function","name":"blob","inputs":[{"name":"v","type":"bytes"}]},
+	{"type":"function","name":"b4","inputs":[{"name":"v","type":"bytes4"}]},
+	{"type":"function","name":"list","inputs":[{"name":"v","type":"uint256[]"}]},
+	{"type":"function","name":"pair","inputs":[{"name":"v","type":"uint8[2]"}]},
+	{"type":"function","name":"tuple","inputs":[{"name":"v","type":"tuple","components":[{"name":"amount","type":"uint256"},{"name":"to","type":"address"}]}]},
+	{"type":"function","name":"two","inputs":[{"name":"a","type":"uint256"},{"name":"b","type":"bool"}]}
+]`
+
+func TestDecodeArguments(t *testing.T) {
+	parsedABI, err := abi.JSON(strings.NewReader(argumentsTestABI))
+	if err != nil {
+		t.Fatalf("abi.JSON: %v", err)
+	}
+	const address = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+
+	tests := []struct {
+		name    string
+		method  string
+		args    string
+		want    string
+		wantErr bool
+	}{
+		{name: "uint8 como número", method: "u8", args: `[255]`, want: "[255]"},
+		{name: "uint8 como string", method: "u8", args: `["7"]`, want: "[7]"},
+		{name: "uint8 fora do intervalo", method: "u8", args: `[256]`, wantErr: true},
+		{name: "uint8 negativo", method: "u8", args: `[-1]`, wantErr: true},
+		{name: "int8 mínimo", method: "i8", args: `[-128]`, want: "[-128]"},
+		{name: "int8 fora do intervalo", method: "i8", args: `[128]`, wantErr: true},
+		{name: "uint256 hexadecimal", method: "u256", args: `["0x2a"]`, want: "[42]"},
+		{name: "uint256 acima de 2^64", method: "u256", args: `["18446744073709551616"]`, want: "[18446744073709551616]"},
+		{name: "uint256 com sinal", method: "u256", args: `["+1"]`, wantErr: true},
+		{name: "uint256 não numérico", method: "u256", args: `["dez"]`, wantErr: true},
+		{name: "int256 negativo em hexadecimal", method: "i256", args: `["-0x10"]`, want: "[-16]"},
+		{name: "booleano", method: "flag", args: `[true]`, want: "[true]"},
+		{name: "booleano como string", method: "flag", args: `["true"]`, wantErr: true},
+		{name: "string", method: "text", args: `["olá"]`, want: "[olá]"},
+		{name: "endereço", method: "addr", args: `["` + address + `"]`, want: "[" + address + "]"},
+		{name: "endereço inválido", method: "addr", args: `["0x123"]`, wantErr: true},
+		{name: "bytes", method: "blob", args: `["0x0102"]`, want: "[[1 2]]"},
+		{name: "bytes sem prefixo", method: "blob", args: `["0102"]`, wantErr: true},
+		{name: "bytes4", method: "b4", args: `["0xdeadbeef"]`, want: "[[222 173 190 239]]"},
+		{name: "bytes4 com tamanho errado", method: "b4", args: `["0xdead"]`, wantErr: true},
+		{name: "lista", method: "list", args: `[["1", 2, "0x3"]]`, want: "[[1 2 3]]"},
+		{name: "lista com item inválido", method: "list", args: `[["1", "x"]]`, wantErr: true},
+		{name: "array de tamanho fixo", method: "pair", args: `[[1, 2]]`, want: "[[1 2]]"},
+		{name: "array com tamanho errado", method: "pair", args: `[[1, 2, 3]]`, wantErr: true},
+		{name: "tupla como objeto", method: "tuple", args: `[{"amount": "5", "to": "` + address + `"}]`, want: "[{5 " + address + "}]"},
+		{name: "tupla como lista", method: "tuple", args: `[["5", "` + address + `"]]`, want: "[{5 " + address + "}]"},
+		{name: "tupla sem campo", method: "tuple", args: `[{"amount": "5"}]`, wantErr: true},
+		{name: "vários argumentos", method: "two", args: `["1", false]`, want: "[1 false]"},
+		{name: "argumentos a menos", method: "two", args: `["1"]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw []json.RawMessage
+			if err := json.Unmarshal([]byte(tt.args), &raw); err != nil {
+				t.Fatalf("argumentos do teste inválidos: %v", err)
+			}
+			inputs := parsedABI.Methods[tt.method].Inputs
+
+			args, err := decodeArguments(inputs, raw)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArguments) {
+					t.Fatalf("esperado ErrInvalidArguments, recebido %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeArguments: %v", err)
+			}
+			if got := fmt.Sprint(args); got != tt.want {
+				t.Errorf("argumentos %s, esperado %s", got, tt.want)
+			}
+			// os tipos Go devolvidos precisam ser os que o go-ethereum aceita na codificação
+			if _, err := inputs.Pack(args...); err != nil {
+				t.Errorf("argumentos não codificam no ABI: %v", err)
+			}
+		})
+	}
+}
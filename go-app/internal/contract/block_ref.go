@@ -0,0 +1,157 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// ErrInvalidBlockRef indica uma referência de bloco que não é número, hash nem tag conhecida
+	ErrInvalidBlockRef = errors.New("referência de bloco inválida")
+	// ErrBlockNotFound indica que o nó não conhece o bloco pedido
+	ErrBlockNotFound = errors.New("bloco não encontrado")
+)
+
+// BlockTag é um bloco identificado pela sua posição relativa à cabeça da cadeia
+type BlockTag string
+
+const (
+	BlockTagLatest    BlockTag = "latest"
+	BlockTagSafe      BlockTag = "safe"
+	BlockTagFinalized BlockTag = "finalized"
+	BlockTagPending   BlockTag = "pending"
+)
+
+// blockTagNumbers traduz as tags para os números especiais aceitos pelo ethclient
+var blockTagNumbers = map[BlockTag]rpc.BlockNumber{
+	BlockTagLatest:    rpc.LatestBlockNumber,
+	BlockTagSafe:      rpc.SafeBlockNumber,
+	BlockTagFinalized: rpc.FinalizedBlockNumber,
+	BlockTagPending:   rpc.PendingBlockNumber,
+}
+
+// BlockRef identifica o bloco em cujo estado uma leitura é feita: por número, por hash ou por tag.
+// O valor zero é o bloco "latest".
+type BlockRef struct {
+	number *uint64
+	hash   *common.Hash
+	tag    BlockTag
+}
+
+// BlockInfo é o bloco em que uma leitura foi efetivamente feita
+type BlockInfo struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// BlockAtNumber referencia o bloco na altura informada
+func BlockAtNumber(number uint64) BlockRef {
+	return BlockRef{number: &number}
+}
+
+// BlockAtHash referencia o bloco com o hash informado (EIP-1898)
+func BlockAtHash(hash common.Hash) BlockRef {
+	return BlockRef{hash: &hash}
+}
+
+// BlockAtTag referencia o bloco da tag informada
+func BlockAtTag(tag BlockTag) BlockRef {
+	return BlockRef{tag: tag}
+}
+
+// ParseBlockRef interpreta um número decimal ou hexadecimal (0x), um hash de 32 bytes ou uma das tags
+// latest, safe, finalized e pending. Uma string vazia é o bloco "latest".
+func ParseBlockRef(raw string) (BlockRef, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return BlockAtTag(BlockTagLatest), nil
+	}
+
+	tag := BlockTag(strings.ToLower(raw))
+	if _, ok := blockTagNumbers[tag]; ok {
+		return BlockAtTag(tag), nil
+	}
+
+	if strings.HasPrefix(raw, "0x") || strings.HasPrefix(raw, "0X") {
+		if len(raw) == 2+2*common.HashLength {
+			hash, err := hexutil.Decode(raw)
+			if err != nil {
+				return BlockRef{}, fmt.Errorf("%w: hash '%s': %v", ErrInvalidBlockRef, raw, err)
+			}
+			return BlockAtHash(common.BytesToHash(hash)), nil
+		}
+		number, err := strconv.ParseUint(raw[2:], 16, 64)
+		if err != nil {
+			return BlockRef{}, fmt.Errorf("%w: número '%s': %v", ErrInvalidBlockRef, raw, err)
+		}
+		return BlockAtNumber(number), nil
+	}
+
+	number, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return BlockRef{}, fmt.Errorf("%w: '%s' não é número, hash nem tag (latest, safe, finalized, pending)", ErrInvalidBlockRef, raw)
+	}
+	return BlockAtNumber(number), nil
+}
+
+// String descreve a referência como aceita por ParseBlockRef
+func (ref BlockRef) String() string {
+	switch {
+	case ref.hash != nil:
+		return ref.hash.Hex()
+	case ref.number != nil:
+		return strconv.FormatUint(*ref.number, 10)
+	case ref.tag != "":
+		return string(ref.tag)
+	default:
+		return string(BlockTagLatest)
+	}
+}
+
+// resolveBlock busca o cabeçalho do bloco referenciado e prepara as opções de leitura naquele bloco.
+// Leituras por número ou tag são fixadas no número do bloco resolvido, para que valor e bloco correspondam.
+func (sc *SmartContract) resolveBlock(ctx context.Context, ref BlockRef) (*bind.CallOpts, BlockInfo, error) {
+	var header *types.Header
+	var err error
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	switch {
+	case ref.hash != nil:
+		header, err = sc.client.HeaderByHash(ctx, *ref.hash)
+	case ref.number != nil:
+		header, err = sc.client.HeaderByNumber(ctx, new(big.Int).SetUint64(*ref.number))
+	default:
+		tag := ref.tag
+		if tag == "" {
+			tag = BlockTagLatest
+		}
+		header, err = sc.client.HeaderByNumber(ctx, big.NewInt(blockTagNumbers[tag].Int64()))
+		callOpts.Pending = tag == BlockTagPending
+	}
+	if errors.Is(err, ethereum.NotFound) || (err == nil && header == nil) {
+		return nil, BlockInfo{}, fmt.Errorf("%w: %s", ErrBlockNotFound, ref)
+	}
+	if err != nil {
+		return nil, BlockInfo{}, fmt.Errorf("erro ao obter cabeçalho do bloco %s: %w", ref, err)
+	}
+
+	block := BlockInfo{Number: header.Number.Uint64(), Hash: header.Hash()}
+	switch {
+	case ref.hash != nil:
+		callOpts.BlockHash = block.Hash
+	case !callOpts.Pending:
+		callOpts.BlockNumber = new(big.Int).Set(header.Number)
+	}
+	return callOpts, block, nil
+}
@@ -0,0 +1,53 @@
+package contract
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseBlockRef(t *testing.T) {
+	const hash = "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{name: "vazio é latest", raw: "", want: "latest"},
+		{name: "espaços é latest", raw: "  ", want: "latest"},
+		{name: "tag latest", raw: "latest", want: "latest"},
+		{name: "tag em maiúsculas", raw: "Finalized", want: "finalized"},
+		{name: "tag safe", raw: "safe", want: "safe"},
+		{name: "tag pending", raw: "pending", want: "pending"},
+		{name: "número decimal", raw: "1234", want: "1234"},
+		{name: "número zero", raw: "0", want: "0"},
+		{name: "número hexadecimal", raw: "0x4d2", want: "1234"},
+		{name: "número hexadecimal com 0X", raw: "0X4D2", want: "1234"},
+		{name: "hash", raw: hash, want: hash},
+		{name: "tag desconhecida", raw: "earliest", wantErr: true},
+		{name: "número negativo", raw: "-1", wantErr: true},
+		{name: "hexadecimal inválido", raw: "0xzz", wantErr: true},
+		{name: "prefixo sem dígitos", raw: "0x", wantErr: true},
+		{name: "hash com caractere inválido", raw: "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a71394zz", wantErr: true},
+		{name: "número acima de uint64", raw: "18446744073709551616", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseBlockRef(tt.raw)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidBlockRef) {
+					t.Fatalf("esperado ErrInvalidBlockRef, recebido %v (%s)", err, ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBlockRef: %v", err)
+			}
+			if ref.String() != tt.want {
+				t.Errorf("referência %s, esperado %s", ref, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// UnsignedTx são os campos de uma transação ainda não assinada, no formato dos parâmetros de
+// eth_sendTransaction/eth_signTransaction (quantidades em hexadecimal), aceito por carteiras como o MetaMask
+type UnsignedTx struct {
+	Type                 hexutil.Uint64 `json:"type"`
+	From                 common.Address `json:"from"`
+	To                   common.Address `json:"to"`
+	Data                 hexutil.Bytes  `json:"data"`
+	Value                *hexutil.Big   `json:"value"`
+	Nonce                hexutil.Uint64 `json:"nonce"`
+	Gas                  hexutil.Uint64 `json:"gas"`
+	GasPrice             *hexutil.Big   `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas,omitempty"`
+	ChainID              *hexutil.Big   `json:"chainId"`
+}
+
+// BuiltTransaction é uma transação montada para ser assinada fora da API
+type BuiltTransaction struct {
+	Tx          UnsignedTx
+	SigningHash common.Hash // hash assinado pela chave de from (EIP-155 nas legadas, EIP-2718 nas tipadas)
+}
+
+// BuildTransaction monta, sem assinar, uma transação de from para a função method do contrato, com os argumentos em
+// JSON, as taxas atuais, o gas limit estimado e o próximo nonce pendente da conta. A transação assinada pelo cliente
+// pode ser enviada por SendRawTransaction; se a conta enviar outras transações antes, o nonce deixa de valer.
+func (sc *SmartContract) BuildTransaction(ctx context.Context, method string, args []json.RawMessage, value *big.Int, from common.Address) (*BuiltTransaction, error) {
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	params, err := sc.mutatingArguments(method, args, value)
+	if err != nil {
+		return nil, err
+	}
+	data, err := sc.parsedABI.Pack(method, params...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao codificar chamada da função '%s': %w", method, err)
+	}
+
+	fees, err := sc.fees.Fees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// assim como em transact, a estimativa simula a chamada e devolve um revert antes de o cliente assinar
+	gasLimit, err := sc.gas.GasLimit(ctx, ethereum.CallMsg{
+		From:      from,
+		To:        &sc.contractAddress,
+		GasPrice:  fees.GasPrice,
+		GasFeeCap: fees.GasFeeCap,
+		GasTipCap: fees.GasTipCap,
+		Value:     value,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, decodeRevert(sc.parsedABI, err)
+	}
+
+	nonce, err := sc.client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar nonce pendente da conta %s: %w", from.Hex(), err)
+	}
+
+	unsigned := UnsignedTx{
+		From:    from,
+		To:      sc.contractAddress,
+		Data:    data,
+		Value:   (*hexutil.Big)(value),
+		Nonce:   hexutil.Uint64(nonce),
+		Gas:     hexutil.Uint64(gasLimit),
+		ChainID: (*hexutil.Big)(sc.chainID),
+	}
+	var txData types.TxData
+	if fees.IsLegacy() {
+		unsigned.Type = types.LegacyTxType
+		unsigned.GasPrice = (*hexutil.Big)(fees.GasPrice)
+		txData = &types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: fees.GasPrice,
+			Gas:      gasLimit,
+			To:       &sc.contractAddress,
+			Value:    value,
+			Data:     data,
+		}
+	} else {
+		unsigned.Type = types.DynamicFeeTxType
+		unsigned.MaxFeePerGas = (*hexutil.Big)(fees.GasFeeCap)
+		unsigned.MaxPriorityFeePerGas = (*hexutil.Big)(fees.GasTipCap)
+		txData = &types.DynamicFeeTx{
+			ChainID:   sc.chainID,
+			Nonce:     nonce,
+			GasTipCap: fees.GasTipCap,
+			GasFeeCap: fees.GasFeeCap,
+			Gas:       gasLimit,
+			To:        &sc.contractAddress,
+			Value:     value,
+			Data:      data,
+		}
+	}
+
+	signingHash := types.LatestSignerForChainID(sc.chainID).Hash(types.NewTx(txData))
+	return &BuiltTransaction{Tx: unsigned, SigningHash: signingHash}, nil
+}
@@ -2,94 +2,128 @@ package contract
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
-	"github.com/ethereum/go-ethereum/crypto"
-	"io/ioutil"
 	"math/big"
-	"strings"
-	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/vmm2136/besu_challenge/go-app/internal/pkg/ethutils"
 )
 
 // ContractClient define a interface para interagir com o contrato
 type ContractClient interface {
+	Name() string
+	SupportsValue() bool
+	SupportsValueChanged() bool
 	GetValue(ctx context.Context) (*big.Int, error)
-	SetValue(ctx context.Context, value *big.Int, privateKey *ecdsa.PrivateKey) (common.Hash, error)
+	GetValueAt(ctx context.Context, blockNumber uint64) (*big.Int, error)
+	GetValueAtBlock(ctx context.Context, ref BlockRef) (*big.Int, BlockInfo, error)
+	LatestBlockNumber(ctx context.Context) (uint64, error)
+	BlockHashAt(ctx context.Context, blockNumber uint64) (common.Hash, error)
+	DeploymentBlock(ctx context.Context) (uint64, error)
+	Address() common.Address
+	FilterValueChanged(ctx context.Context, fromBlock, toBlock uint64) ([]ValueChangedEvent, error)
+	WatchValueChanged(ctx context.Context) (<-chan ValueChangedEvent, error)
+	NewHeads() (<-chan *types.Header, func())
+	SetValue(ctx context.Context, value *big.Int, transactors Transactors) (common.Hash, error)
+	TransactionStatus(ctx context.Context, hash common.Hash) (*TxStatus, error)
+	WaitForTransaction(ctx context.Context, hash common.Hash) (*TxStatus, error)
+	WaitForConfirmation(ctx context.Context, hash common.Hash) (*TxStatus, error)
+	SpeedUpTransaction(ctx context.Context, hash common.Hash, signer ethutils.Signer) (common.Hash, error)
+	CancelTransaction(ctx context.Context, hash common.Hash, signer ethutils.Signer) (common.Hash, error)
+	TransactionValueChanges(ctx context.Context, hash common.Hash) ([]ValueChangedEvent, error)
+	Methods() []MethodInfo
+	CallMethod(ctx context.Context, method string, args []json.RawMessage) ([]MethodOutput, error)
+	TransactMethod(ctx context.Context, method string, args []json.RawMessage, value *big.Int, transactors Transactors) (common.Hash, error)
+	BuildTransaction(ctx context.Context, method string, args []json.RawMessage, value *big.Int, from common.Address) (*BuiltTransaction, error)
+	SendRawTransaction(ctx context.Context, tx *types.Transaction, allowed func(method string) bool) (RawTransaction, error)
 }
 
-// SmartContract implementa ContractClient para o contrato SimpleStorage
+// SmartContract implementa ContractClient para um contrato implantado na rede
 type SmartContract struct {
-	client          *ethclient.Client
+	*TxMonitor
+	name            string
 	contractAddress common.Address
 	parsedABI       abi.ABI
 	chainID         *big.Int
+	fees            *FeeOracle
+	gas             *GasEstimator
 }
 
-// NewSmartContract cria uma nova instância de SmartContract
-func NewSmartContract(nodeURL, abiPath, addressPath string) (*SmartContract, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	client, err := ethclient.DialContext(ctx, nodeURL)
-	if err != nil {
-		return nil, fmt.Errorf("erro conectando ao client Besu: %w", err)
-	}
-
-	chainID, err := client.ChainID(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao obter Chain ID da rede: %w", err)
-	}
-
-	abiData, err := ioutil.ReadFile(abiPath)
-	if err != nil {
-		return nil, fmt.Errorf("erro lendo ABI do contrato: %w", err)
-	}
-
-	var artifact struct {
-		ABI json.RawMessage `json:"abi"`
-	}
-	if err := json.Unmarshal(abiData, &artifact); err != nil {
-		return nil, fmt.Errorf("erro parseando ABI do contrato: %w", err)
-	}
-
-	parsedABI, err := abi.JSON(strings.NewReader(string(artifact.ABI)))
-	if err != nil {
-		return nil, fmt.Errorf("erro convertendo ABI: %w", err)
-	}
-
-	addressData, err := ioutil.ReadFile(addressPath)
-	if err != nil {
-		return nil, fmt.Errorf("erro lendo endereço do contrato: %w", err)
+// NewSmartContract cria uma nova instância de SmartContract.
+// O monitor de transações (com o client, o rastreador e o gerenciador de nonces), o oráculo de taxas e o estimador
+// de gás são compartilhados entre os contratos do Registry.
+func NewSmartContract(name string, monitor *TxMonitor, chainID *big.Int, address common.Address, parsedABI abi.ABI, fees *FeeOracle, gas *GasEstimator) (*SmartContract, error) {
+	if monitor == nil {
+		return nil, fmt.Errorf("monitor de transações não pode ser nulo")
 	}
-
-	var deploymentMap map[string]string
-	if err := json.Unmarshal(addressData, &deploymentMap); err != nil {
-		return nil, fmt.Errorf("erro lendo JSON de endereço do contrato: %w", err)
+	if fees == nil {
+		return nil, fmt.Errorf("oráculo de taxas não pode ser nulo")
 	}
-
-	var contractAddress string
-	for _, addr := range deploymentMap {
-		contractAddress = addr
-		break
+	if gas == nil {
+		return nil, fmt.Errorf("estimador de gás não pode ser nulo")
 	}
 
 	return &SmartContract{
-		client:          client,
-		contractAddress: common.HexToAddress(contractAddress),
+		TxMonitor:       monitor,
+		name:            name,
+		contractAddress: address,
 		parsedABI:       parsedABI,
 		chainID:         chainID,
+		fees:            fees,
+		gas:             gas,
 	}, nil
 }
 
+// Name retorna o identificador do contrato no Registry
+func (sc *SmartContract) Name() string {
+	return sc.name
+}
+
+// SupportsValue indica se o contrato expõe as funções 'get' e 'set' usadas pelos endpoints de valor
+func (sc *SmartContract) SupportsValue() bool {
+	_, hasGet := sc.parsedABI.Methods["get"]
+	_, hasSet := sc.parsedABI.Methods["set"]
+	return hasGet && hasSet
+}
+
+// SupportsValueChanged indica se o ABI do contrato declara o evento ValueChanged
+func (sc *SmartContract) SupportsValueChanged() bool {
+	_, ok := sc.parsedABI.Events[ValueChangedEventName]
+	return ok
+}
+
 // GetValue busca o valor atual do contrato
 func (sc *SmartContract) GetValue(ctx context.Context) (*big.Int, error) {
-	callOpts := &bind.CallOpts{Context: ctx}
+	return sc.callGet(&bind.CallOpts{Context: ctx})
+}
+
+// GetValueAt busca o valor do contrato no estado de um bloco específico
+func (sc *SmartContract) GetValueAt(ctx context.Context, blockNumber uint64) (*big.Int, error) {
+	return sc.callGet(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNumber)})
+}
+
+// GetValueAtBlock busca o valor do contrato no bloco referenciado por número, hash ou tag
+// e retorna também o número e o hash do bloco em que a leitura foi feita
+func (sc *SmartContract) GetValueAtBlock(ctx context.Context, ref BlockRef) (*big.Int, BlockInfo, error) {
+	callOpts, block, err := sc.resolveBlock(ctx, ref)
+	if err != nil {
+		return nil, BlockInfo{}, err
+	}
+
+	value, err := sc.callGet(callOpts)
+	if err != nil {
+		return nil, BlockInfo{}, err
+	}
+	return value, block, nil
+}
+
+// callGet chama a função 'get' do contrato com as opções de leitura informadas
+func (sc *SmartContract) callGet(callOpts *bind.CallOpts) (*big.Int, error) {
 	bound := bind.NewBoundContract(sc.contractAddress, sc.parsedABI, sc.client, sc.client, sc.client)
 
 	var out []interface{}
@@ -109,41 +143,184 @@ func (sc *SmartContract) GetValue(ctx context.Context) (*big.Int, error) {
 	return val, nil
 }
 
-// SetValue define um novo valor no contrato
-func (sc *SmartContract) SetValue(ctx context.Context, value *big.Int, privateKey *ecdsa.PrivateKey) (common.Hash, error) {
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return common.Hash{}, fmt.Errorf("erro ao converter chave pública para ECDSA")
+// Address retorna o endereço do contrato na rede
+func (sc *SmartContract) Address() common.Address {
+	return sc.contractAddress
+}
+
+// LatestBlockNumber retorna o número do último bloco conhecido pelo nó
+func (sc *SmartContract) LatestBlockNumber(ctx context.Context) (uint64, error) {
+	head, err := sc.client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao obter número do último bloco: %w", err)
 	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
+	return head, nil
+}
 
-	nonce, err := sc.client.PendingNonceAt(ctx, fromAddress)
+// BlockHashAt retorna o hash do bloco canônico na altura informada
+func (sc *SmartContract) BlockHashAt(ctx context.Context, blockNumber uint64) (common.Hash, error) {
+	header, err := sc.client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("erro ao obter nonce da conta %s: %w", fromAddress.Hex(), err)
+		return common.Hash{}, fmt.Errorf("erro ao obter cabeçalho do bloco %d: %w", blockNumber, err)
 	}
+	return header.Hash(), nil
+}
 
-	gasPrice, err := sc.client.SuggestGasPrice(ctx)
+// DeploymentBlock encontra, por busca binária no código do endereço, o bloco em que o contrato foi implantado.
+// Depende de o nó responder eth_getCode em blocos antigos (estado histórico disponível).
+func (sc *SmartContract) DeploymentBlock(ctx context.Context) (uint64, error) {
+	head, err := sc.LatestBlockNumber(ctx)
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("erro ao obter gas price: %w", err)
+		return 0, err
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, sc.chainID)
+	hasCode := func(blockNumber uint64) (bool, error) {
+		code, err := sc.client.CodeAt(ctx, sc.contractAddress, new(big.Int).SetUint64(blockNumber))
+		if err != nil {
+			return false, fmt.Errorf("erro ao obter código do contrato no bloco %d: %w", blockNumber, err)
+		}
+		return len(code) > 0, nil
+	}
+
+	deployed, err := hasCode(head)
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("erro ao criar transactor com ChainID %s: %w", sc.chainID.String(), err)
+		return 0, err
+	}
+	if !deployed {
+		return 0, fmt.Errorf("endereço %s não possui código no bloco %d", sc.contractAddress.Hex(), head)
+	}
+
+	// invariante: o contrato existe em high e não existe antes de low
+	low, high := uint64(0), head
+	for low < high {
+		mid := low + (high-low)/2
+		deployed, err := hasCode(mid)
+		if err != nil {
+			return 0, err
+		}
+		if deployed {
+			high = mid
+		} else {
+			low = mid + 1
+		}
 	}
+	return high, nil
+}
 
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)
-	auth.GasLimit = uint64(300000)
-	auth.GasPrice = gasPrice
+// SetValue define um novo valor no contrato, assinado por uma conta escolhida em transactors
+func (sc *SmartContract) SetValue(ctx context.Context, value *big.Int, transactors Transactors) (common.Hash, error) {
+	if value == nil || value.Sign() < 0 || value.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("valor fora do intervalo de um uint256: %v", value)
+	}
+	return sc.transact(ctx, transactors, big.NewInt(0), "set", value)
+}
 
-	bound := bind.NewBoundContract(sc.contractAddress, sc.parsedABI, sc.client, sc.client, sc.client)
+// transact assina e envia uma transação chamando a função method do contrato com os argumentos já convertidos.
+// A conta que assina é escolhida em transactors entre as que têm saldo para o custo máximo da transação.
+// O nonce vem do NonceManager; a transação assinada é gravada no journal antes do envio e, depois de enviada,
+// passa a ser acompanhada pelo TxTracker.
+func (sc *SmartContract) transact(ctx context.Context, transactors Transactors, value *big.Int, method string, args ...interface{}) (common.Hash, error) {
+	fees, err := sc.fees.Fees(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	data, err := sc.parsedABI.Pack(method, args...)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("erro ao codificar chamada da função '%s': %w", method, err)
+	}
 
-	tx, err := bound.Transact(auth, "set", value)
+	// a estimativa simula a transação: um revert é detectado e devolvido aqui, antes de qualquer envio
+	var gasLimit uint64
+	signer, err := transactors.Select(ctx, func(from common.Address) (*big.Int, error) {
+		gasLimit, err = sc.gas.GasLimit(ctx, ethereum.CallMsg{
+			From:      from,
+			To:        &sc.contractAddress,
+			GasPrice:  fees.GasPrice,
+			GasFeeCap: fees.GasFeeCap,
+			GasTipCap: fees.GasTipCap,
+			Value:     value,
+			Data:      data,
+		})
+		if err != nil {
+			return nil, decodeRevert(sc.parsedABI, err)
+		}
+		return fees.UpfrontCost(gasLimit, value), nil
+	})
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("erro ao executar transação 'set' no contrato: %w", err)
+		return common.Hash{}, err
 	}
+	fromAddress := signer.Address()
+
+	auth := &bind.TransactOpts{
+		From:    fromAddress,
+		Context: ctx,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return signer.SignTx(ctx, tx, sc.chainID)
+		},
+		Value:    value,
+		GasLimit: gasLimit,
+		NoSend:   true, // a transação é só assinada aqui; o envio acontece depois de gravada no journal
+	}
+	if fees.IsLegacy() {
+		auth.GasPrice = fees.GasPrice
+	} else {
+		auth.GasTipCap = fees.GasTipCap
+		auth.GasFeeCap = fees.GasFeeCap
+	}
+
+	bound := bind.NewBoundContract(sc.contractAddress, sc.parsedABI, sc.client, sc.client, sc.client)
+
+	var tx *types.Transaction
+	for attempt := 1; ; attempt++ {
+		nonce, err := sc.nonces.Next(ctx, fromAddress)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		auth.Nonce = new(big.Int).SetUint64(nonce)
+
+		tx, err = bound.RawTransact(auth, data)
+		if err != nil {
+			sc.nonces.Release(fromAddress, nonce)
+			return common.Hash{}, fmt.Errorf("erro ao assinar transação '%s': %w", method, err)
+		}
+		if err := sc.tracker.Prepare(ctx, tx, fromAddress, method); err != nil {
+			sc.nonces.Release(fromAddress, nonce)
+			return common.Hash{}, err
+		}
+
+		err = sc.client.SendTransaction(ctx, tx)
+		if err == nil {
+			sc.nonces.Done(fromAddress, nonce)
+			break
+		}
+		if !isRejectedError(err) {
+			// o nó pode ter aceitado a transação antes da falha: ela segue acompanhada e o nonce não é reutilizado
+			sc.nonces.Done(fromAddress, nonce)
+			sc.tracker.Track(tx, fromAddress)
+			go sc.watchTransaction(tx.Hash())
+			return common.Hash{}, fmt.Errorf("%w: transação '%s' %s: %v", ErrSendOutcomeUnknown, method, tx.Hash().Hex(), err)
+		}
+		sc.tracker.fail(ctx, tx.Hash())
+
+		if !isNonceError(err) {
+			sc.nonces.Release(fromAddress, nonce)
+			return common.Hash{}, fmt.Errorf("erro ao executar transação '%s' no contrato: %w", method, decodeRevert(sc.parsedABI, err))
+		}
+		// o nonce já foi usado pela conta e não volta para a fila
+		sc.nonces.Done(fromAddress, nonce)
+		if attempt == maxNonceRetries {
+			return common.Hash{}, fmt.Errorf("erro ao executar transação '%s' no contrato: %w", method, err)
+		}
+
+		fmt.Printf("Nonce %d rejeitado para a conta %s (%v). Ressincronizando com o nó...\n", nonce, fromAddress.Hex(), err)
+		if err := sc.nonces.Resync(ctx, fromAddress); err != nil {
+			return common.Hash{}, err
+		}
+	}
+
+	sc.tracker.Track(tx, fromAddress)
+	go sc.watchTransaction(tx.Hash())
 
 	return tx.Hash(), nil
 }
@@ -0,0 +1,159 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ValueChangedEventName é o nome do evento emitido pelo contrato SimpleStorage a cada chamada de set
+const ValueChangedEventName = "ValueChanged"
+
+// ValueChangedEvent representa um log ValueChanged decodificado
+type ValueChangedEvent struct {
+	ContractAddress common.Address
+	BlockNumber     uint64
+	BlockHash       common.Hash
+	TxHash          common.Hash
+	LogIndex        uint
+	Setter          common.Address
+	OldValue        *big.Int
+	NewValue        *big.Int
+}
+
+// FilterValueChanged busca e decodifica os eventos ValueChanged emitidos entre fromBlock e toBlock (inclusive)
+func (sc *SmartContract) FilterValueChanged(ctx context.Context, fromBlock, toBlock uint64) ([]ValueChangedEvent, error) {
+	event, ok := sc.parsedABI.Events[ValueChangedEventName]
+	if !ok {
+		return nil, fmt.Errorf("evento '%s' não existe no ABI do contrato", ValueChangedEventName)
+	}
+
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{sc.contractAddress},
+		Topics:    [][]common.Hash{{event.ID}},
+	}
+
+	logs, err := sc.client.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar logs '%s' entre os blocos %d e %d: %w", ValueChangedEventName, fromBlock, toBlock, err)
+	}
+
+	events := make([]ValueChangedEvent, 0, len(logs))
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		decoded, err := sc.decodeValueChanged(log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, decoded)
+	}
+	return events, nil
+}
+
+// WatchValueChanged assina os eventos ValueChanged do contrato a partir do bloco atual e os entrega decodificados
+// até ctx ser cancelado, quando o canal é fechado. Exige um endpoint WebSocket ou IPC (ErrSubscriptionsUnsupported).
+func (sc *SmartContract) WatchValueChanged(ctx context.Context) (<-chan ValueChangedEvent, error) {
+	event, ok := sc.parsedABI.Events[ValueChangedEventName]
+	if !ok {
+		return nil, fmt.Errorf("evento '%s' não existe no ABI do contrato", ValueChangedEventName)
+	}
+
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{sc.contractAddress},
+		Topics:    [][]common.Hash{{event.ID}},
+	}
+	logs, err := sc.subscriptions.SubscribeLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan ValueChangedEvent, 16)
+	go func() {
+		defer close(events)
+		for log := range logs {
+			if log.Removed {
+				continue
+			}
+			decoded, err := sc.decodeValueChanged(log)
+			if err != nil {
+				fmt.Printf("Erro ao decodificar evento recebido por assinatura: %v\n", err)
+				continue
+			}
+			select {
+			case events <- decoded:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// TransactionValueChanges decodifica os eventos ValueChanged presentes no recibo de uma transação minerada
+func (sc *SmartContract) TransactionValueChanges(ctx context.Context, hash common.Hash) ([]ValueChangedEvent, error) {
+	event, ok := sc.parsedABI.Events[ValueChangedEventName]
+	if !ok {
+		return nil, nil
+	}
+
+	receipt, err := sc.client.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar recibo da transação %s: %w", hash.Hex(), err)
+	}
+
+	var events []ValueChangedEvent
+	for _, log := range receipt.Logs {
+		if log.Address != sc.contractAddress || len(log.Topics) == 0 || log.Topics[0] != event.ID {
+			continue
+		}
+		decoded, err := sc.decodeValueChanged(*log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, decoded)
+	}
+	return events, nil
+}
+
+// decodeValueChanged decodifica um log ValueChanged usando o ABI do contrato
+func (sc *SmartContract) decodeValueChanged(log types.Log) (ValueChangedEvent, error) {
+	if len(log.Topics) < 2 {
+		return ValueChangedEvent{}, fmt.Errorf("log '%s' sem o tópico do setter (tx %s, índice %d)", ValueChangedEventName, log.TxHash.Hex(), log.Index)
+	}
+
+	values, err := sc.parsedABI.Unpack(ValueChangedEventName, log.Data)
+	if err != nil {
+		return ValueChangedEvent{}, fmt.Errorf("erro ao decodificar log '%s' (tx %s, índice %d): %w", ValueChangedEventName, log.TxHash.Hex(), log.Index, err)
+	}
+	if len(values) != 2 {
+		return ValueChangedEvent{}, fmt.Errorf("log '%s' com %d valores, esperado 2", ValueChangedEventName, len(values))
+	}
+
+	oldValue, ok := values[0].(*big.Int)
+	if !ok {
+		return ValueChangedEvent{}, fmt.Errorf("tipo inesperado para oldValue no log '%s': %T", ValueChangedEventName, values[0])
+	}
+	newValue, ok := values[1].(*big.Int)
+	if !ok {
+		return ValueChangedEvent{}, fmt.Errorf("tipo inesperado para newValue no log '%s': %T", ValueChangedEventName, values[1])
+	}
+
+	return ValueChangedEvent{
+		ContractAddress: log.Address,
+		BlockNumber:     log.BlockNumber,
+		BlockHash:       log.BlockHash,
+		TxHash:          log.TxHash,
+		LogIndex:        log.Index,
+		Setter:          common.BytesToAddress(log.Topics[1].Bytes()),
+		OldValue:        oldValue,
+		NewValue:        newValue,
+	}, nil
+}
@@ -0,0 +1,261 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Estratégias de cálculo das taxas de gás aceitas em FEE_STRATEGY
+const (
+	FeeStrategyFixed      = "fixed"      // valores fixos definidos na configuração
+	FeeStrategySuggested  = "suggested"  // valores sugeridos pelo nó (eth_gasPrice / eth_maxPriorityFeePerGas)
+	FeeStrategyPercentile = "percentile" // percentil das gorjetas dos últimos blocos (eth_feeHistory)
+)
+
+// ErrFeeAboveCeiling indica que a taxa necessária ultrapassa o teto configurado
+var ErrFeeAboveCeiling = errors.New("taxa necessária acima do teto configurado")
+
+// FeeSource é a parte do client Ethereum usada para calcular as taxas das transações
+type FeeSource interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+}
+
+// FeeConfig configura o FeeOracle. Tetos nulos ou zero não limitam as taxas.
+type FeeConfig struct {
+	Strategy      string
+	FixedGasPrice *big.Int // usado em transações legacy pela estratégia fixed
+	FixedTipCap   *big.Int // usado em transações EIP-1559 pela estratégia fixed
+	Percentile    float64  // percentil das gorjetas para a estratégia percentile
+	HistoryBlocks uint64   // quantidade de blocos consultados pela estratégia percentile
+	MaxFeeCap     *big.Int // teto para GasFeeCap (EIP-1559) e GasPrice (legacy)
+	MaxTipCap     *big.Int // teto para GasTipCap
+	PriceBump     uint64   // aumento mínimo (%) exigido pelo nó para substituir uma transação pendente
+}
+
+// TxFees são as taxas de uma transação. GasPrice preenchido indica transação legacy;
+// caso contrário a transação é do tipo 2 (EIP-1559) com GasTipCap e GasFeeCap.
+type TxFees struct {
+	GasPrice  *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+// IsLegacy indica se as taxas são de uma transação legacy
+func (f *TxFees) IsLegacy() bool {
+	return f.GasPrice != nil
+}
+
+// UpfrontCost retorna o custo máximo de uma transação com estas taxas: gasLimit × preço máximo do gás + value
+func (f *TxFees) UpfrontCost(gasLimit uint64, value *big.Int) *big.Int {
+	price := f.GasFeeCap
+	if f.IsLegacy() {
+		price = f.GasPrice
+	}
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), price)
+	if value != nil {
+		cost.Add(cost, value)
+	}
+	return cost
+}
+
+// FeeOracle calcula as taxas das transações de acordo com a estratégia configurada e o suporte da rede ao London
+type FeeOracle struct {
+	source FeeSource
+	config FeeConfig
+}
+
+// NewFeeOracle cria um FeeOracle validando a estratégia configurada
+func NewFeeOracle(source FeeSource, config FeeConfig) (*FeeOracle, error) {
+	switch config.Strategy {
+	case FeeStrategyFixed:
+		if config.FixedGasPrice == nil || config.FixedTipCap == nil {
+			return nil, fmt.Errorf("estratégia de taxas 'fixed' exige gas price e tip cap fixos")
+		}
+	case FeeStrategySuggested:
+	case FeeStrategyPercentile:
+		if config.Percentile < 0 || config.Percentile > 100 {
+			return nil, fmt.Errorf("percentil de taxas deve estar entre 0 e 100: %v", config.Percentile)
+		}
+		if config.HistoryBlocks == 0 {
+			return nil, fmt.Errorf("a estratégia de taxas 'percentile' exige ao menos 1 bloco de histórico")
+		}
+	default:
+		return nil, fmt.Errorf("estratégia de taxas '%s' inválida (use '%s', '%s' ou '%s')",
+			config.Strategy, FeeStrategyFixed, FeeStrategySuggested, FeeStrategyPercentile)
+	}
+	return &FeeOracle{source: source, config: config}, nil
+}
+
+// Fees calcula as taxas da próxima transação. Redes sem base fee no último bloco (pré-London) e redes Besu
+// de gás gratuito (base fee zero) recebem transações legacy; as demais recebem transações EIP-1559.
+func (o *FeeOracle) Fees(ctx context.Context) (*TxFees, error) {
+	head, err := o.source.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter último bloco para cálculo das taxas: %w", err)
+	}
+
+	if head.BaseFee == nil || head.BaseFee.Sign() == 0 {
+		gasPrice, err := o.gasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &TxFees{GasPrice: capFee(gasPrice, o.config.MaxFeeCap)}, nil
+	}
+
+	tipCap, err := o.tipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tipCap = capFee(tipCap, o.config.MaxTipCap)
+
+	// 2x a base fee atual acomoda seis blocos cheios seguidos antes que a transação fique abaixo da base fee
+	feeCap := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tipCap)
+	feeCap = capFee(feeCap, o.config.MaxFeeCap)
+	if feeCap.Cmp(head.BaseFee) < 0 {
+		return nil, fmt.Errorf("teto de taxa %s é menor que a base fee atual %s", feeCap.String(), head.BaseFee.String())
+	}
+	if tipCap.Cmp(feeCap) > 0 {
+		tipCap = new(big.Int).Set(feeCap)
+	}
+	return &TxFees{GasTipCap: tipCap, GasFeeCap: feeCap}, nil
+}
+
+// ReplacementFees calcula as taxas de uma transação que substitui original (mesmo nonce). Cada taxa é a maior entre
+// a taxa atual da estratégia e a taxa original acrescida do PriceBump, e o tipo da transação original é mantido.
+func (o *FeeOracle) ReplacementFees(ctx context.Context, original *types.Transaction) (*TxFees, error) {
+	current, err := o.Fees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if original.Type() == types.LegacyTxType {
+		suggested := current.GasPrice
+		if suggested == nil {
+			suggested = current.GasFeeCap
+		}
+		gasPrice := maxFee(suggested, bumpFee(original.GasPrice(), o.config.PriceBump))
+		if err := o.checkReplacementCap(gasPrice, o.config.MaxFeeCap); err != nil {
+			return nil, err
+		}
+		return &TxFees{GasPrice: gasPrice}, nil
+	}
+
+	suggestedTip, suggestedFeeCap := current.GasTipCap, current.GasFeeCap
+	if current.IsLegacy() {
+		suggestedTip, suggestedFeeCap = current.GasPrice, current.GasPrice
+	}
+	tipCap := maxFee(suggestedTip, bumpFee(original.GasTipCap(), o.config.PriceBump))
+	feeCap := maxFee(suggestedFeeCap, bumpFee(original.GasFeeCap(), o.config.PriceBump))
+	if tipCap.Cmp(feeCap) > 0 {
+		feeCap = new(big.Int).Set(tipCap)
+	}
+	if err := o.checkReplacementCap(tipCap, o.config.MaxTipCap); err != nil {
+		return nil, err
+	}
+	if err := o.checkReplacementCap(feeCap, o.config.MaxFeeCap); err != nil {
+		return nil, err
+	}
+	return &TxFees{GasTipCap: tipCap, GasFeeCap: feeCap}, nil
+}
+
+// checkReplacementCap impede uma substituição cujas taxas mínimas já ultrapassam o teto configurado
+func (o *FeeOracle) checkReplacementCap(value, ceiling *big.Int) error {
+	if ceiling != nil && ceiling.Sign() > 0 && value.Cmp(ceiling) > 0 {
+		return fmt.Errorf("%w: a substituição exige %s wei e o teto é %s wei", ErrFeeAboveCeiling, value.String(), ceiling.String())
+	}
+	return nil
+}
+
+// gasPrice retorna o gas price de uma transação legacy
+func (o *FeeOracle) gasPrice(ctx context.Context) (*big.Int, error) {
+	if o.config.Strategy == FeeStrategyFixed {
+		return new(big.Int).Set(o.config.FixedGasPrice), nil
+	}
+
+	gasPrice, err := o.source.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter gas price: %w", err)
+	}
+	return gasPrice, nil
+}
+
+// tipCap retorna a gorjeta (priority fee) de uma transação EIP-1559
+func (o *FeeOracle) tipCap(ctx context.Context) (*big.Int, error) {
+	switch o.config.Strategy {
+	case FeeStrategyFixed:
+		return new(big.Int).Set(o.config.FixedTipCap), nil
+	case FeeStrategyPercentile:
+		return o.percentileTipCap(ctx)
+	}
+
+	tipCap, err := o.source.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter priority fee sugerida: %w", err)
+	}
+	return tipCap, nil
+}
+
+// percentileTipCap usa a mediana, entre os últimos blocos, do percentil configurado das gorjetas de cada bloco.
+// Se nenhum bloco recente tiver transações, recorre à gorjeta sugerida pelo nó.
+func (o *FeeOracle) percentileTipCap(ctx context.Context) (*big.Int, error) {
+	history, err := o.source.FeeHistory(ctx, o.config.HistoryBlocks, nil, []float64{o.config.Percentile})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar eth_feeHistory: %w", err)
+	}
+
+	var rewards []*big.Int
+	for i, blockRewards := range history.Reward {
+		if len(blockRewards) == 0 || blockRewards[0] == nil {
+			continue
+		}
+		if i < len(history.GasUsedRatio) && history.GasUsedRatio[i] == 0 {
+			continue // bloco vazio: a gorjeta reportada é zero e não reflete a demanda
+		}
+		rewards = append(rewards, blockRewards[0])
+	}
+
+	if len(rewards) == 0 {
+		tipCap, err := o.source.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao obter priority fee sugerida: %w", err)
+		}
+		return tipCap, nil
+	}
+
+	sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+	return new(big.Int).Set(rewards[len(rewards)/2]), nil
+}
+
+// bumpFee acrescenta percent% a value, arredondando para cima
+func bumpFee(value *big.Int, percent uint64) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+	bumped := new(big.Int).Mul(value, new(big.Int).SetUint64(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func maxFee(a, b *big.Int) *big.Int {
+	if a == nil || a.Cmp(b) < 0 {
+		return new(big.Int).Set(b)
+	}
+	return new(big.Int).Set(a)
+}
+
+// capFee limita value ao teto informado; teto nulo ou zero não limita
+func capFee(value, ceiling *big.Int) *big.Int {
+	if ceiling == nil || ceiling.Sign() == 0 || value.Cmp(ceiling) <= 0 {
+		return value
+	}
+	return new(big.Int).Set(ceiling)
+}
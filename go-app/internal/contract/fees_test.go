@@ -0,0 +1,274 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// fakeFeeSource responde as taxas configuradas no teste
+type fakeFeeSource struct {
+	baseFee  *big.Int
+	gasPrice *big.Int
+	tipCap   *big.Int
+	history  *ethereum.FeeHistory
+}
+
+func (s *fakeFeeSource) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: s.baseFee}, nil
+}
+
+func (s *fakeFeeSource) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return s.gasPrice, nil
+}
+
+func (s *fakeFeeSource) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return s.tipCap, nil
+}
+
+func (s *fakeFeeSource) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	if s.history == nil {
+		return nil, errors.New("eth_feeHistory indisponível")
+	}
+	return s.history, nil
+}
+
+// feeHistory monta um eth_feeHistory com uma gorjeta (do percentil pedido) e a ocupação de cada bloco
+func feeHistory(rewards []int64, gasUsedRatio []float64) *ethereum.FeeHistory {
+	history := &ethereum.FeeHistory{GasUsedRatio: gasUsedRatio}
+	for _, reward := range rewards {
+		history.Reward = append(history.Reward, []*big.Int{big.NewInt(reward)})
+	}
+	return history
+}
+
+func TestFeeOracleFees(t *testing.T) {
+	suggested := FeeConfig{Strategy: FeeStrategySuggested}
+	percentile := FeeConfig{Strategy: FeeStrategyPercentile, Percentile: 50, HistoryBlocks: 3}
+
+	tests := []struct {
+		name    string
+		config  FeeConfig
+		source  fakeFeeSource
+		want    TxFees
+		wantErr bool
+	}{
+		{
+			name:   "rede sem London usa gas price sugerido",
+			config: suggested,
+			source: fakeFeeSource{gasPrice: big.NewInt(10)},
+			want:   TxFees{GasPrice: big.NewInt(10)},
+		},
+		{
+			name:   "base fee zero usa gas price fixo",
+			config: FeeConfig{Strategy: FeeStrategyFixed, FixedGasPrice: big.NewInt(7), FixedTipCap: big.NewInt(1)},
+			source: fakeFeeSource{baseFee: big.NewInt(0)},
+			want:   TxFees{GasPrice: big.NewInt(7)},
+		},
+		{
+			name:   "gas price limitado ao teto",
+			config: FeeConfig{Strategy: FeeStrategySuggested, MaxFeeCap: big.NewInt(5)},
+			source: fakeFeeSource{gasPrice: big.NewInt(10)},
+			want:   TxFees{GasPrice: big.NewInt(5)},
+		},
+		{
+			name:   "EIP-1559 com gorjeta sugerida",
+			config: suggested,
+			source: fakeFeeSource{baseFee: big.NewInt(100), tipCap: big.NewInt(2)},
+			want:   TxFees{GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(202)},
+		},
+		{
+			name:   "EIP-1559 com gorjeta fixa",
+			config: FeeConfig{Strategy: FeeStrategyFixed, FixedGasPrice: big.NewInt(7), FixedTipCap: big.NewInt(3)},
+			source: fakeFeeSource{baseFee: big.NewInt(100)},
+			want:   TxFees{GasTipCap: big.NewInt(3), GasFeeCap: big.NewInt(203)},
+		},
+		{
+			name:   "gorjeta limitada ao teto",
+			config: FeeConfig{Strategy: FeeStrategySuggested, MaxTipCap: big.NewInt(1)},
+			source: fakeFeeSource{baseFee: big.NewInt(100), tipCap: big.NewInt(2)},
+			want:   TxFees{GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(201)},
+		},
+		{
+			name:   "fee cap limitado ao teto",
+			config: FeeConfig{Strategy: FeeStrategySuggested, MaxFeeCap: big.NewInt(150)},
+			source: fakeFeeSource{baseFee: big.NewInt(100), tipCap: big.NewInt(2)},
+			want:   TxFees{GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(150)},
+		},
+		{
+			name:   "gorjeta nunca passa do fee cap",
+			config: FeeConfig{Strategy: FeeStrategySuggested, MaxFeeCap: big.NewInt(30)},
+			source: fakeFeeSource{baseFee: big.NewInt(10), tipCap: big.NewInt(50)},
+			want:   TxFees{GasTipCap: big.NewInt(30), GasFeeCap: big.NewInt(30)},
+		},
+		{
+			name:    "teto abaixo da base fee",
+			config:  FeeConfig{Strategy: FeeStrategySuggested, MaxFeeCap: big.NewInt(90)},
+			source:  fakeFeeSource{baseFee: big.NewInt(100), tipCap: big.NewInt(2)},
+			wantErr: true,
+		},
+		{
+			name:   "percentil usa a mediana dos blocos",
+			config: percentile,
+			source: fakeFeeSource{baseFee: big.NewInt(100), history: feeHistory([]int64{5, 1, 9}, []float64{0.5, 0.5, 0.5})},
+			want:   TxFees{GasTipCap: big.NewInt(5), GasFeeCap: big.NewInt(205)},
+		},
+		{
+			name:   "percentil ignora blocos vazios",
+			config: percentile,
+			source: fakeFeeSource{baseFee: big.NewInt(100), history: feeHistory([]int64{0, 4, 8}, []float64{0, 0.5, 0.5})},
+			want:   TxFees{GasTipCap: big.NewInt(8), GasFeeCap: big.NewInt(208)},
+		},
+		{
+			name:   "percentil sem blocos com transações usa a gorjeta sugerida",
+			config: percentile,
+			source: fakeFeeSource{baseFee: big.NewInt(100), tipCap: big.NewInt(2), history: feeHistory([]int64{0, 0, 0}, []float64{0, 0, 0})},
+			want:   TxFees{GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(202)},
+		},
+		{
+			name:    "falha do eth_feeHistory",
+			config:  percentile,
+			source:  fakeFeeSource{baseFee: big.NewInt(100)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oracle, err := NewFeeOracle(&tt.source, tt.config)
+			if err != nil {
+				t.Fatalf("NewFeeOracle: %v", err)
+			}
+			fees, err := oracle.Fees(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("esperado erro, recebido %+v", fees)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fees: %v", err)
+			}
+			checkFees(t, fees, &tt.want)
+		})
+	}
+}
+
+func TestNewFeeOracleInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config FeeConfig
+	}{
+		{name: "estratégia desconhecida", config: FeeConfig{Strategy: "dynamic"}},
+		{name: "fixed sem gas price", config: FeeConfig{Strategy: FeeStrategyFixed, FixedTipCap: big.NewInt(1)}},
+		{name: "percentil acima de 100", config: FeeConfig{Strategy: FeeStrategyPercentile, Percentile: 101, HistoryBlocks: 1}},
+		{name: "percentil negativo", config: FeeConfig{Strategy: FeeStrategyPercentile, Percentile: -1, HistoryBlocks: 1}},
+		{name: "percentil sem blocos de histórico", config: FeeConfig{Strategy: FeeStrategyPercentile, Percentile: 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFeeOracle(&fakeFeeSource{}, tt.config); err == nil {
+				t.Error("esperado erro")
+			}
+		})
+	}
+}
+
+func TestFeeOracleReplacementFees(t *testing.T) {
+	to := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	legacy := types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(100), To: &to})
+	dynamic := types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(10), GasFeeCap: big.NewInt(100), To: &to})
+
+	tests := []struct {
+		name     string
+		config   FeeConfig
+		source   fakeFeeSource
+		original *types.Transaction
+		want     TxFees
+		wantErr  error
+	}{
+		{
+			name:     "legada com aumento mínimo",
+			config:   FeeConfig{Strategy: FeeStrategySuggested, PriceBump: 10},
+			source:   fakeFeeSource{gasPrice: big.NewInt(50)},
+			original: legacy,
+			want:     TxFees{GasPrice: big.NewInt(110)},
+		},
+		{
+			name:     "legada com taxa atual maior",
+			config:   FeeConfig{Strategy: FeeStrategySuggested, PriceBump: 10},
+			source:   fakeFeeSource{gasPrice: big.NewInt(200)},
+			original: legacy,
+			want:     TxFees{GasPrice: big.NewInt(200)},
+		},
+		{
+			name:     "EIP-1559 com aumento mínimo",
+			config:   FeeConfig{Strategy: FeeStrategySuggested, PriceBump: 10},
+			source:   fakeFeeSource{baseFee: big.NewInt(10), tipCap: big.NewInt(2)},
+			original: dynamic,
+			want:     TxFees{GasTipCap: big.NewInt(11), GasFeeCap: big.NewInt(110)},
+		},
+		{
+			name:     "EIP-1559 acima do teto",
+			config:   FeeConfig{Strategy: FeeStrategySuggested, PriceBump: 10, MaxFeeCap: big.NewInt(105)},
+			source:   fakeFeeSource{baseFee: big.NewInt(10), tipCap: big.NewInt(2)},
+			original: dynamic,
+			wantErr:  ErrFeeAboveCeiling,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oracle, err := NewFeeOracle(&tt.source, tt.config)
+			if err != nil {
+				t.Fatalf("NewFeeOracle: %v", err)
+			}
+			fees, err := oracle.ReplacementFees(context.Background(), tt.original)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("esperado %v, recebido %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReplacementFees: %v", err)
+			}
+			checkFees(t, fees, &tt.want)
+		})
+	}
+}
+
+func TestBumpFee(t *testing.T) {
+	tests := []struct {
+		value   *big.Int
+		percent uint64
+		want    int64
+	}{
+		{value: big.NewInt(100), percent: 10, want: 110},
+		{value: big.NewInt(101), percent: 10, want: 112}, // 111,1 arredondado para cima
+		{value: big.NewInt(100), percent: 0, want: 100},
+		{value: nil, percent: 10, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := bumpFee(tt.value, tt.percent); got.Cmp(big.NewInt(tt.want)) != 0 {
+			t.Errorf("bumpFee(%v, %d) = %s, esperado %d", tt.value, tt.percent, got, tt.want)
+		}
+	}
+}
+
+func checkFees(t *testing.T, got, want *TxFees) {
+	t.Helper()
+	equal := func(a, b *big.Int) bool {
+		return (a == nil && b == nil) || (a != nil && b != nil && a.Cmp(b) == 0)
+	}
+	if !equal(got.GasPrice, want.GasPrice) || !equal(got.GasTipCap, want.GasTipCap) || !equal(got.GasFeeCap, want.GasFeeCap) {
+		t.Errorf("taxas %+v, esperado %+v", *got, *want)
+	}
+}
@@ -0,0 +1,84 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+var (
+	// ErrGasAboveCeiling indica que a estimativa de gás da transação ultrapassa o teto configurado
+	ErrGasAboveCeiling = errors.New("estimativa de gás acima do teto configurado")
+	// ErrGasLimitTooLow indica que o gas limit de uma transação assinada pelo cliente não cobre a estimativa do nó
+	ErrGasLimitTooLow = errors.New("gas limit insuficiente")
+)
+
+// GasSource é a parte do client Ethereum usada para estimar o gás das transações
+type GasSource interface {
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+}
+
+// GasConfig configura o GasEstimator
+type GasConfig struct {
+	Multiplier float64 // margem de segurança aplicada sobre a estimativa do nó (ex.: 1.2 = +20%)
+	Ceiling    uint64  // teto do gas limit; zero não limita
+}
+
+// GasEstimator calcula o gas limit das transações a partir de eth_estimateGas com uma margem de segurança
+type GasEstimator struct {
+	source GasSource
+	config GasConfig
+}
+
+// NewGasEstimator cria um GasEstimator validando a configuração
+func NewGasEstimator(source GasSource, config GasConfig) (*GasEstimator, error) {
+	if config.Multiplier < 1 {
+		return nil, fmt.Errorf("multiplicador de gás deve ser maior ou igual a 1: %v", config.Multiplier)
+	}
+	return &GasEstimator{source: source, config: config}, nil
+}
+
+// GasLimit estima o gás da chamada e aplica o multiplicador, limitado ao teto configurado.
+// Se a chamada seria revertida, retorna um *RevertError com os dados do revert, antes de qualquer envio.
+func (e *GasEstimator) GasLimit(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	estimate, err := e.source.EstimateGas(ctx, msg)
+	if err != nil {
+		if revertErr, ok := revertFromRPCError(err); ok {
+			return 0, revertErr
+		}
+		return 0, fmt.Errorf("erro ao estimar gás da transação: %w", err)
+	}
+
+	if e.config.Ceiling > 0 && estimate > e.config.Ceiling {
+		return 0, fmt.Errorf("%w: estimado %d, teto %d", ErrGasAboveCeiling, estimate, e.config.Ceiling)
+	}
+
+	gasLimit := uint64(math.Ceil(float64(estimate) * e.config.Multiplier))
+	if e.config.Ceiling > 0 && gasLimit > e.config.Ceiling {
+		gasLimit = e.config.Ceiling
+	}
+	return gasLimit, nil
+}
+
+// CheckGasLimit confere o gas limit escolhido por quem assinou a transação: ele precisa cobrir a estimativa do nó
+// e respeitar o teto configurado. Assim como GasLimit, um revert é retornado como *RevertError.
+func (e *GasEstimator) CheckGasLimit(ctx context.Context, msg ethereum.CallMsg, gasLimit uint64) error {
+	if e.config.Ceiling > 0 && gasLimit > e.config.Ceiling {
+		return fmt.Errorf("%w: gas limit %d, teto %d", ErrGasAboveCeiling, gasLimit, e.config.Ceiling)
+	}
+
+	estimate, err := e.source.EstimateGas(ctx, msg)
+	if err != nil {
+		if revertErr, ok := revertFromRPCError(err); ok {
+			return revertErr
+		}
+		return fmt.Errorf("erro ao estimar gás da transação: %w", err)
+	}
+	if estimate > gasLimit {
+		return fmt.Errorf("%w: gas limit %d abaixo da estimativa de %d", ErrGasLimitTooLow, gasLimit, estimate)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// fakeGasSource responde a estimativa (ou o erro) configurada no teste
+type fakeGasSource struct {
+	estimate uint64
+	err      error
+}
+
+func (s *fakeGasSource) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return s.estimate, s.err
+}
+
+func TestGasEstimatorGasLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  GasConfig
+		source  fakeGasSource
+		want    uint64
+		wantErr error
+	}{
+		{name: "sem margem", config: GasConfig{Multiplier: 1}, source: fakeGasSource{estimate: 21000}, want: 21000},
+		{name: "margem de 20%", config: GasConfig{Multiplier: 1.2}, source: fakeGasSource{estimate: 50000}, want: 60000},
+		{name: "margem arredondada para cima", config: GasConfig{Multiplier: 1.1}, source: fakeGasSource{estimate: 21001}, want: 23102},
+		{name: "margem limitada ao teto", config: GasConfig{Multiplier: 1.5, Ceiling: 70000}, source: fakeGasSource{estimate: 50000}, want: 70000},
+		{name: "estimativa igual ao teto", config: GasConfig{Multiplier: 1.2, Ceiling: 50000}, source: fakeGasSource{estimate: 50000}, want: 50000},
+		{
+			name:    "estimativa acima do teto",
+			config:  GasConfig{Multiplier: 1.2, Ceiling: 50000},
+			source:  fakeGasSource{estimate: 50001},
+			wantErr: ErrGasAboveCeiling,
+		},
+		{
+			name:    "revert na estimativa",
+			config:  GasConfig{Multiplier: 1.2},
+			source:  fakeGasSource{err: rpcTestError{"execution reverted", "0x"}},
+			wantErr: ErrExecutionReverted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimator, err := NewGasEstimator(&tt.source, tt.config)
+			if err != nil {
+				t.Fatalf("NewGasEstimator: %v", err)
+			}
+			gasLimit, err := estimator.GasLimit(context.Background(), ethereum.CallMsg{})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("esperado %v, recebido %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GasLimit: %v", err)
+			}
+			if gasLimit != tt.want {
+				t.Errorf("gas limit %d, esperado %d", gasLimit, tt.want)
+			}
+		})
+	}
+}
+
+func TestGasEstimatorEstimateError(t *testing.T) {
+	estimator, err := NewGasEstimator(&fakeGasSource{err: errors.New("connection refused")}, GasConfig{Multiplier: 1})
+	if err != nil {
+		t.Fatalf("NewGasEstimator: %v", err)
+	}
+	_, err = estimator.GasLimit(context.Background(), ethereum.CallMsg{})
+	if err == nil || errors.Is(err, ErrExecutionReverted) {
+		t.Errorf("falha de comunicação não deve virar revert: %v", err)
+	}
+}
+
+func TestGasEstimatorCheckGasLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   GasConfig
+		source   fakeGasSource
+		gasLimit uint64
+		wantErr  error
+	}{
+		{name: "cobre a estimativa", config: GasConfig{Multiplier: 1}, source: fakeGasSource{estimate: 21000}, gasLimit: 21000},
+		{name: "abaixo da estimativa", config: GasConfig{Multiplier: 1}, source: fakeGasSource{estimate: 21000}, gasLimit: 20999, wantErr: ErrGasLimitTooLow},
+		{name: "acima do teto", config: GasConfig{Multiplier: 1, Ceiling: 30000}, source: fakeGasSource{estimate: 21000}, gasLimit: 30001, wantErr: ErrGasAboveCeiling},
+		{
+			name:     "revert na simulação",
+			config:   GasConfig{Multiplier: 1},
+			source:   fakeGasSource{err: rpcTestError{"execution reverted", "0x"}},
+			gasLimit: 21000,
+			wantErr:  ErrExecutionReverted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimator, err := NewGasEstimator(&tt.source, tt.config)
+			if err != nil {
+				t.Fatalf("NewGasEstimator: %v", err)
+			}
+			err = estimator.CheckGasLimit(context.Background(), ethereum.CallMsg{}, tt.gasLimit)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("CheckGasLimit: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("esperado %v, recebido %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewGasEstimatorMultiplier(t *testing.T) {
+	if _, err := NewGasEstimator(&fakeGasSource{}, GasConfig{Multiplier: 0.9}); err == nil {
+		t.Error("multiplicador menor que 1 deveria ser recusado")
+	}
+}
@@ -0,0 +1,91 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxRecord é o registro persistido de uma transação assinada pela API
+type TxRecord struct {
+	Hash           common.Hash
+	From           common.Address
+	To             *common.Address
+	Nonce          uint64
+	Type           uint8
+	Raw            []byte // transação assinada, como enviada ao nó
+	GasLimit       uint64
+	GasPrice       *big.Int
+	GasTipCap      *big.Int
+	GasFeeCap      *big.Int
+	Value          *big.Int
+	Method         string
+	Requester      string
+	IdempotencyKey string
+	State          TxState
+	BlockNumber    uint64
+	BlockHash      common.Hash
+	ReplacedBy     common.Hash
+	Replaces       common.Hash
+	SubmittedAt    time.Time
+}
+
+// TxJournal persiste as transações assinadas pela API, para que sobrevivam a reinícios
+type TxJournal interface {
+	SaveTransaction(ctx context.Context, record TxRecord) error
+	UpdateTransactionState(ctx context.Context, status TxStatus) error
+	ListOpenTransactions(ctx context.Context) ([]TxRecord, error)
+}
+
+// TxMetadata identifica a requisição que originou uma transação
+type TxMetadata struct {
+	Requester      string
+	IdempotencyKey string
+}
+
+type txMetadataKey struct{}
+
+// WithTxMetadata anexa ao contexto os dados da requisição que serão gravados junto com as transações enviadas
+func WithTxMetadata(ctx context.Context, metadata TxMetadata) context.Context {
+	return context.WithValue(ctx, txMetadataKey{}, metadata)
+}
+
+func txMetadataFromContext(ctx context.Context) TxMetadata {
+	metadata, _ := ctx.Value(txMetadataKey{}).(TxMetadata)
+	return metadata
+}
+
+// newTxRecord monta o registro de uma transação assinada, ainda não enviada
+func newTxRecord(ctx context.Context, tx *types.Transaction, from common.Address, method string) (TxRecord, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return TxRecord{}, err
+	}
+
+	metadata := txMetadataFromContext(ctx)
+	record := TxRecord{
+		Hash:           tx.Hash(),
+		From:           from,
+		To:             tx.To(),
+		Nonce:          tx.Nonce(),
+		Type:           tx.Type(),
+		Raw:            raw,
+		GasLimit:       tx.Gas(),
+		Value:          tx.Value(),
+		Method:         method,
+		Requester:      metadata.Requester,
+		IdempotencyKey: metadata.IdempotencyKey,
+		State:          TxStateSubmitted,
+		SubmittedAt:    time.Now(),
+	}
+	if tx.Type() == types.LegacyTxType {
+		record.GasPrice = tx.GasPrice()
+	} else {
+		record.GasTipCap = tx.GasTipCap()
+		record.GasFeeCap = tx.GasFeeCap()
+	}
+	return record, nil
+}
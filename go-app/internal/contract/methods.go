@@ -0,0 +1,108 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Erros da chamada genérica de funções do contrato
+var (
+	ErrMethodNotFound    = errors.New("função não encontrada no ABI do contrato")
+	ErrMethodNotView     = errors.New("função altera o estado do contrato; use /transact")
+	ErrMethodNotMutating = errors.New("função é somente leitura; use /call")
+	ErrMethodNotPayable  = errors.New("função não é payable e não aceita value")
+	ErrInvalidArguments  = errors.New("argumentos inválidos")
+)
+
+// MethodInfo descreve uma função do ABI do contrato
+type MethodInfo struct {
+	Name            string   `json:"name"`
+	Signature       string   `json:"signature"`
+	StateMutability string   `json:"state_mutability"`
+	Inputs          []string `json:"inputs"`
+	Outputs         []string `json:"outputs"`
+}
+
+// Methods lista as funções do ABI do contrato em ordem alfabética
+func (sc *SmartContract) Methods() []MethodInfo {
+	methods := make([]MethodInfo, 0, len(sc.parsedABI.Methods))
+	for name, method := range sc.parsedABI.Methods {
+		methods = append(methods, MethodInfo{
+			Name:            name,
+			Signature:       method.Sig,
+			StateMutability: method.StateMutability,
+			Inputs:          argumentTypes(method.Inputs),
+			Outputs:         argumentTypes(method.Outputs),
+		})
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+	return methods
+}
+
+// CallMethod executa uma função view/pure do contrato com argumentos em JSON e retorna as saídas convertidas para JSON
+func (sc *SmartContract) CallMethod(ctx context.Context, method string, args []json.RawMessage) ([]MethodOutput, error) {
+	abiMethod, ok := sc.parsedABI.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotFound, method)
+	}
+	if !abiMethod.IsConstant() {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotView, method)
+	}
+
+	params, err := decodeArguments(abiMethod.Inputs, args)
+	if err != nil {
+		return nil, err
+	}
+
+	bound := bind.NewBoundContract(sc.contractAddress, sc.parsedABI, sc.client, sc.client, sc.client)
+
+	var out []interface{}
+	if err := bound.Call(&bind.CallOpts{Context: ctx}, &out, method, params...); err != nil {
+		return nil, fmt.Errorf("erro ao chamar função '%s' do contrato: %w", method, decodeRevert(sc.parsedABI, err))
+	}
+	return encodeOutputs(abiMethod.Outputs, out), nil
+}
+
+// TransactMethod envia uma transação para uma função que altera o estado do contrato, com argumentos em JSON.
+// value é a quantidade de wei enviada junto e só é aceita por funções payable.
+func (sc *SmartContract) TransactMethod(ctx context.Context, method string, args []json.RawMessage, value *big.Int, transactors Transactors) (common.Hash, error) {
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	params, err := sc.mutatingArguments(method, args, value)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return sc.transact(ctx, transactors, value, method, params...)
+}
+
+// mutatingArguments confere que method altera o estado e aceita value, e converte os argumentos em JSON
+func (sc *SmartContract) mutatingArguments(method string, args []json.RawMessage, value *big.Int) ([]interface{}, error) {
+	abiMethod, ok := sc.parsedABI.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotFound, method)
+	}
+	if abiMethod.IsConstant() {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotMutating, method)
+	}
+	if value.Sign() > 0 && !abiMethod.IsPayable() {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotPayable, method)
+	}
+	return decodeArguments(abiMethod.Inputs, args)
+}
+
+func argumentTypes(arguments abi.Arguments) []string {
+	types := make([]string, len(arguments))
+	for i, argument := range arguments {
+		types[i] = argument.Type.String()
+	}
+	return types
+}
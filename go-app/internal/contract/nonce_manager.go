@@ -0,0 +1,187 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxNonceRetries é o número de tentativas de envio quando o nó rejeita o nonce usado
+const maxNonceRetries = 3
+
+// NonceSource é a parte do client Ethereum usada para sincronizar nonces com o nó
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager distribui nonces em sequência por conta, permitindo envios concorrentes com a mesma chave
+type NonceManager struct {
+	mu       sync.Mutex
+	source   NonceSource
+	accounts map[common.Address]*accountNonces
+}
+
+// accountNonces guarda o próximo nonce de uma conta, os nonces devolvidos por envios que falharam
+// e os reservados cujo envio ainda não terminou
+type accountNonces struct {
+	next     uint64
+	synced   bool
+	released []uint64
+	inFlight map[uint64]struct{}
+}
+
+// NewNonceManager cria um NonceManager que busca o nonce inicial de cada conta no nó
+func NewNonceManager(source NonceSource) *NonceManager {
+	return &NonceManager{
+		source:   source,
+		accounts: make(map[common.Address]*accountNonces),
+	}
+}
+
+// Next reserva o próximo nonce da conta. Nonces devolvidos por envios que falharam são reutilizados primeiro,
+// para que nenhuma lacuna impeça a mineração das transações seguintes.
+// O nonce reservado deve ser encerrado com Release (não chegou ao nó) ou Done (consumido).
+func (m *NonceManager) Next(ctx context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc := m.account(account)
+	if !acc.synced {
+		if err := m.syncLocked(ctx, account, acc); err != nil {
+			return 0, err
+		}
+	}
+
+	var nonce uint64
+	if len(acc.released) > 0 {
+		nonce = acc.released[0]
+		acc.released = acc.released[1:]
+	} else {
+		nonce = acc.next
+		acc.next++
+	}
+	acc.inFlight[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// Release devolve um nonce reservado cuja transação comprovadamente não chegou ao nó
+func (m *NonceManager) Release(account common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc := m.account(account)
+	delete(acc.inFlight, nonce)
+	if !acc.synced || nonce >= acc.next {
+		return
+	}
+	if nonce == acc.next-1 {
+		acc.next--
+		return
+	}
+	for _, released := range acc.released {
+		if released == nonce {
+			return
+		}
+	}
+	acc.released = append(acc.released, nonce)
+	sort.Slice(acc.released, func(i, j int) bool { return acc.released[i] < acc.released[j] })
+}
+
+// Done encerra a reserva de um nonce que não deve ser reutilizado: a transação chegou ao nó,
+// o resultado do envio é desconhecido ou o nó já tinha o nonce como usado
+func (m *NonceManager) Done(account common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.account(account).inFlight, nonce)
+}
+
+// Resync avança a sequência da conta até o nonce pendente informado pelo nó, sem nunca recuar: nonces reservados
+// por envios concorrentes continuam válidos e não são distribuídos de novo. Nonces devolvidos que o nó já
+// considera usados são descartados.
+func (m *NonceManager) Resync(ctx context.Context, account common.Address) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc := m.account(account)
+	if !acc.synced {
+		return m.syncLocked(ctx, account, acc)
+	}
+
+	pending, err := m.source.PendingNonceAt(ctx, account)
+	if err != nil {
+		return fmt.Errorf("erro ao obter nonce da conta %s: %w", account.Hex(), err)
+	}
+	if pending > acc.next {
+		acc.next = pending
+	}
+	released := acc.released[:0]
+	for _, nonce := range acc.released {
+		if nonce >= pending {
+			released = append(released, nonce)
+		}
+	}
+	acc.released = released
+	return nil
+}
+
+// Reset volta a sequência da conta ao nonce pendente do nó, mesmo que ele seja menor que o próximo nonce local
+// (ex.: depois que uma transação foi descartada e deixou uma lacuna). Só acontece se nenhum nonce da conta estiver
+// reservado; caso contrário retorna false e nada muda.
+func (m *NonceManager) Reset(ctx context.Context, account common.Address) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc := m.account(account)
+	if len(acc.inFlight) > 0 {
+		return false, nil
+	}
+	if err := m.syncLocked(ctx, account, acc); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *NonceManager) account(account common.Address) *accountNonces {
+	acc, ok := m.accounts[account]
+	if !ok {
+		acc = &accountNonces{inFlight: make(map[uint64]struct{})}
+		m.accounts[account] = acc
+	}
+	return acc
+}
+
+func (m *NonceManager) syncLocked(ctx context.Context, account common.Address, acc *accountNonces) error {
+	nonce, err := m.source.PendingNonceAt(ctx, account)
+	if err != nil {
+		return fmt.Errorf("erro ao obter nonce da conta %s: %w", account.Hex(), err)
+	}
+	acc.next = nonce
+	acc.released = nil
+	acc.synced = true
+	return nil
+}
+
+// isNonceError indica se o nó rejeitou a transação porque o nonce já foi usado pela conta
+func isNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
+
+// isRejectedError indica se o próprio nó respondeu o envio com um erro JSON-RPC, ou seja, recusou a transação.
+// Outras falhas (tempo esgotado, conexão interrompida) não dizem se o nó chegou a aceitá-la.
+func isRejectedError(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return true
+	}
+	var dataErr rpc.DataError
+	return errors.As(err, &dataErr)
+}
@@ -0,0 +1,196 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeNonceSource responde o nonce pendente configurado no teste
+type fakeNonceSource struct {
+	pending uint64
+	err     error
+}
+
+func (s *fakeNonceSource) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return s.pending, s.err
+}
+
+// nonceOp é um passo do teste: next (nonce é o esperado), release, done, resync ou reset (reset é o retorno esperado).
+// pending, quando não nulo, muda o nonce pendente do nó antes do passo.
+type nonceOp struct {
+	op      string
+	nonce   uint64
+	pending *uint64
+	reset   bool
+}
+
+func pendingAt(nonce uint64) *uint64 { return &nonce }
+
+func TestNonceManager(t *testing.T) {
+	tests := []struct {
+		name    string
+		pending uint64
+		ops     []nonceOp
+	}{
+		{
+			name:    "sequência a partir do nonce pendente",
+			pending: 5,
+			ops:     []nonceOp{{op: "next", nonce: 5}, {op: "next", nonce: 6}, {op: "next", nonce: 7}},
+		},
+		{
+			name:    "release do último nonce recua a sequência",
+			pending: 5,
+			ops:     []nonceOp{{op: "next", nonce: 5}, {op: "next", nonce: 6}, {op: "release", nonce: 6}, {op: "next", nonce: 6}},
+		},
+		{
+			name:    "nonce devolvido no meio é reutilizado primeiro",
+			pending: 5,
+			ops: []nonceOp{
+				{op: "next", nonce: 5}, {op: "next", nonce: 6}, {op: "next", nonce: 7},
+				{op: "release", nonce: 6}, {op: "next", nonce: 6}, {op: "next", nonce: 8},
+			},
+		},
+		{
+			name:    "release de nonce já devolvido não duplica",
+			pending: 5,
+			ops: []nonceOp{
+				{op: "next", nonce: 5}, {op: "next", nonce: 6}, {op: "next", nonce: 7},
+				{op: "release", nonce: 5}, {op: "release", nonce: 5}, {op: "next", nonce: 5}, {op: "next", nonce: 8},
+			},
+		},
+		{
+			name:    "resync avança até o nonce pendente do nó",
+			pending: 5,
+			ops:     []nonceOp{{op: "next", nonce: 5}, {op: "resync", pending: pendingAt(9)}, {op: "next", nonce: 9}},
+		},
+		{
+			name:    "resync não recua sobre nonces reservados",
+			pending: 5,
+			ops: []nonceOp{
+				{op: "next", nonce: 5}, {op: "next", nonce: 6},
+				{op: "resync", pending: pendingAt(5)}, {op: "next", nonce: 7},
+			},
+		},
+		{
+			name:    "resync descarta nonces devolvidos que o nó já usou",
+			pending: 5,
+			ops: []nonceOp{
+				{op: "next", nonce: 5}, {op: "next", nonce: 6}, {op: "next", nonce: 7},
+				{op: "release", nonce: 5}, {op: "resync", pending: pendingAt(6)}, {op: "next", nonce: 8},
+			},
+		},
+		{
+			name:    "reset com nonce reservado não muda a sequência",
+			pending: 5,
+			ops: []nonceOp{
+				{op: "next", nonce: 5}, {op: "next", nonce: 6}, {op: "done", nonce: 5},
+				{op: "reset", pending: pendingAt(5), reset: false}, {op: "next", nonce: 7},
+			},
+		},
+		{
+			name:    "reset sem reservas volta ao nonce pendente do nó",
+			pending: 5,
+			ops: []nonceOp{
+				{op: "next", nonce: 5}, {op: "next", nonce: 6}, {op: "done", nonce: 5}, {op: "done", nonce: 6},
+				{op: "reset", pending: pendingAt(6), reset: true}, {op: "next", nonce: 6},
+			},
+		},
+	}
+
+	account := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeNonceSource{pending: tt.pending}
+			manager := NewNonceManager(source)
+			ctx := context.Background()
+
+			for i, step := range tt.ops {
+				if step.pending != nil {
+					source.pending = *step.pending
+				}
+				switch step.op {
+				case "next":
+					nonce, err := manager.Next(ctx, account)
+					if err != nil {
+						t.Fatalf("passo %d: Next: %v", i, err)
+					}
+					if nonce != step.nonce {
+						t.Fatalf("passo %d: Next = %d, esperado %d", i, nonce, step.nonce)
+					}
+				case "release":
+					manager.Release(account, step.nonce)
+				case "done":
+					manager.Done(account, step.nonce)
+				case "resync":
+					if err := manager.Resync(ctx, account); err != nil {
+						t.Fatalf("passo %d: Resync: %v", i, err)
+					}
+				case "reset":
+					reset, err := manager.Reset(ctx, account)
+					if err != nil {
+						t.Fatalf("passo %d: Reset: %v", i, err)
+					}
+					if reset != step.reset {
+						t.Fatalf("passo %d: Reset = %v, esperado %v", i, reset, step.reset)
+					}
+				default:
+					t.Fatalf("passo %d: operação desconhecida %s", i, step.op)
+				}
+			}
+		})
+	}
+}
+
+func TestNonceManagerSourceError(t *testing.T) {
+	source := &fakeNonceSource{err: errors.New("conexão recusada")}
+	manager := NewNonceManager(source)
+	account := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+	if _, err := manager.Next(context.Background(), account); err == nil {
+		t.Fatal("Next deveria falhar quando o nó não responde o nonce")
+	}
+
+	// depois que o nó volta, a conta é sincronizada na próxima reserva
+	source.err = nil
+	source.pending = 3
+	nonce, err := manager.Next(context.Background(), account)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if nonce != 3 {
+		t.Errorf("Next = %d, esperado 3", nonce)
+	}
+}
+
+func TestSendErrorClassification(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantNonce    bool
+		wantRejected bool
+	}{
+		{name: "nonce too low", err: rpcTestError{"nonce too low", nil}, wantNonce: true, wantRejected: true},
+		{name: "Nonce too low do Besu", err: rpcTestError{"Nonce too low", nil}, wantNonce: true, wantRejected: true},
+		{name: "replacement underpriced", err: rpcTestError{"replacement transaction underpriced", nil}, wantRejected: true},
+		{name: "revert", err: rpcTestError{"execution reverted", "0x"}, wantRejected: true},
+		{name: "erro JSON-RPC encapsulado", err: fmt.Errorf("envio: %w", rpcTestError{"nonce too low", nil}), wantNonce: true, wantRejected: true},
+		{name: "tempo esgotado", err: context.DeadlineExceeded},
+		{name: "conexão interrompida", err: errors.New("connection reset by peer")},
+		{name: "sem erro", err: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNonceError(tt.err); got != tt.wantNonce {
+				t.Errorf("isNonceError = %v, esperado %v", got, tt.wantNonce)
+			}
+			if got := isRejectedError(tt.err); got != tt.wantRejected {
+				t.Errorf("isRejectedError = %v, esperado %v", got, tt.wantRejected)
+			}
+		})
+	}
+}
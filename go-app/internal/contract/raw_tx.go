@@ -0,0 +1,157 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Erros da validação de transações assinadas pelo cliente
+var (
+	ErrInvalidRawTx     = errors.New("transação assinada inválida")
+	ErrChainIDMismatch  = errors.New("chain ID da transação difere do da rede")
+	ErrNonceTooLow      = errors.New("nonce já usado pela conta")
+	ErrNonceGap         = errors.New("nonce deixa uma lacuna na sequência da conta")
+	ErrMethodNotAllowed = errors.New("função não permitida em transações assinadas pelo cliente")
+)
+
+// RawTransaction descreve uma transação assinada pelo cliente e aceita para envio
+type RawTransaction struct {
+	Hash   common.Hash
+	From   common.Address
+	Nonce  uint64
+	Method string
+}
+
+// RawNonceSource é a parte do client usada para conferir o nonce das transações assinadas pelo cliente
+type RawNonceSource interface {
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// DecodeRawTransaction decodifica uma transação assinada no formato de eth_sendRawTransaction
+// (RLP para transações legadas, tipo seguido do RLP para as tipadas)
+func DecodeRawTransaction(raw []byte) (*types.Transaction, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%w: transação vazia", ErrInvalidRawTx)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRawTx, err)
+	}
+	return tx, nil
+}
+
+// SendRawTransaction valida e envia uma transação assinada pelo cliente para uma função deste contrato.
+// allowed diz quais funções são aceitas; nil recusa todas.
+// Antes do envio são conferidos o chain ID, o remetente, a função chamada, o nonce da conta e o gas limit (com a
+// simulação da chamada); depois, a transação é gravada no journal e acompanhada como as assinadas pela API.
+func (sc *SmartContract) SendRawTransaction(ctx context.Context, tx *types.Transaction, allowed func(method string) bool) (RawTransaction, error) {
+	from, method, err := sc.checkRawTransaction(ctx, tx, allowed, sc.client)
+	if err != nil {
+		return RawTransaction{}, err
+	}
+
+	if err := sc.tracker.Prepare(ctx, tx, from, method.Name); err != nil {
+		return RawTransaction{}, err
+	}
+	if err := sc.client.SendTransaction(ctx, tx); err != nil {
+		if !isRejectedError(err) {
+			sc.tracker.Track(tx, from)
+			go sc.watchTransaction(tx.Hash())
+			return RawTransaction{}, fmt.Errorf("%w: transação '%s' %s: %v", ErrSendOutcomeUnknown, method.Name, tx.Hash().Hex(), err)
+		}
+		sc.tracker.fail(ctx, tx.Hash())
+		return RawTransaction{}, fmt.Errorf("erro ao enviar transação '%s' ao nó: %w", method.Name, decodeRevert(sc.parsedABI, err))
+	}
+
+	sc.tracker.Track(tx, from)
+	go sc.watchTransaction(tx.Hash())
+
+	return RawTransaction{Hash: tx.Hash(), From: from, Nonce: tx.Nonce(), Method: method.Name}, nil
+}
+
+// checkRawTransaction faz as validações de SendRawTransaction anteriores ao envio e retorna o remetente e a função
+// chamada. nonces é consultado para conferir o nonce da conta.
+func (sc *SmartContract) checkRawTransaction(ctx context.Context, tx *types.Transaction, allowed func(method string) bool, nonces RawNonceSource) (common.Address, *abi.Method, error) {
+	if tx.To() == nil || *tx.To() != sc.contractAddress {
+		return common.Address{}, nil, fmt.Errorf("%w: destino não é o contrato %s", ErrInvalidRawTx, sc.name)
+	}
+
+	if !tx.Protected() {
+		return common.Address{}, nil, fmt.Errorf("%w: transação sem proteção contra replay (EIP-155)", ErrChainIDMismatch)
+	}
+	if tx.ChainId().Cmp(sc.chainID) != 0 {
+		return common.Address{}, nil, fmt.Errorf("%w: transação para a rede %s, esperado %s", ErrChainIDMismatch, tx.ChainId(), sc.chainID)
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(sc.chainID), tx)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("%w: assinatura inválida: %v", ErrInvalidRawTx, err)
+	}
+
+	data := tx.Data()
+	if len(data) < 4 {
+		return common.Address{}, nil, fmt.Errorf("%w: transação sem seletor de função", ErrInvalidRawTx)
+	}
+	method, err := sc.parsedABI.MethodById(data[:4])
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("%w: seletor %x", ErrMethodNotFound, data[:4])
+	}
+	if method.IsConstant() {
+		return common.Address{}, nil, fmt.Errorf("%w: %s", ErrMethodNotMutating, method.Name)
+	}
+	if tx.Value().Sign() > 0 && !method.IsPayable() {
+		return common.Address{}, nil, fmt.Errorf("%w: %s", ErrMethodNotPayable, method.Name)
+	}
+	if allowed == nil || !allowed(method.Name) {
+		return common.Address{}, nil, fmt.Errorf("%w: %s", ErrMethodNotAllowed, method.Name)
+	}
+	if _, err := method.Inputs.Unpack(data[4:]); err != nil {
+		return common.Address{}, nil, fmt.Errorf("%w: %s: %v", ErrInvalidArguments, method.Name, err)
+	}
+
+	if err := checkRawNonce(ctx, nonces, from, tx.Nonce()); err != nil {
+		return common.Address{}, nil, err
+	}
+
+	// a simulação detecta reverts e gas limit insuficiente antes de qualquer envio
+	msg := ethereum.CallMsg{From: from, To: tx.To(), Value: tx.Value(), Data: data}
+	if tx.Type() == types.LegacyTxType {
+		msg.GasPrice = tx.GasPrice()
+	} else {
+		msg.GasFeeCap = tx.GasFeeCap()
+		msg.GasTipCap = tx.GasTipCap()
+	}
+	if err := sc.gas.CheckGasLimit(ctx, msg, tx.Gas()); err != nil {
+		return common.Address{}, nil, decodeRevert(sc.parsedABI, err)
+	}
+	return from, method, nil
+}
+
+// checkRawNonce aceita o próximo nonce da conta ou o de uma transação ainda pendente (que será substituída);
+// nonces já minerados e nonces que deixariam a transação presa na fila do nó são recusados
+func checkRawNonce(ctx context.Context, nonces RawNonceSource, from common.Address, nonce uint64) error {
+	mined, err := nonces.NonceAt(ctx, from, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao consultar nonce da conta %s: %w", from.Hex(), err)
+	}
+	if nonce < mined {
+		return fmt.Errorf("%w: nonce %d, próximo nonce de %s é %d", ErrNonceTooLow, nonce, from.Hex(), mined)
+	}
+
+	pending, err := nonces.PendingNonceAt(ctx, from)
+	if err != nil {
+		return fmt.Errorf("erro ao consultar nonce pendente da conta %s: %w", from.Hex(), err)
+	}
+	if nonce > pending {
+		return fmt.Errorf("%w: nonce %d, próximo nonce de %s é %d", ErrNonceGap, nonce, from.Hex(), pending)
+	}
+	return nil
+}
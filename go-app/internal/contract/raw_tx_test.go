@@ -0,0 +1,207 @@
+package contract
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const rawTxTestABI = `[
+	{"type":"function","name":"set","stateMutability":"nonpayable","inputs":[{"name":"value","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"reset","stateMutability":"nonpayable","inputs":[],"outputs":[]},
+	{"type":"function","name":"get","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// fakeRawNonceSource responde o nonce minerado e o pendente configurados no teste
+type fakeRawNonceSource struct {
+	mined   uint64
+	pending uint64
+}
+
+func (s *fakeRawNonceSource) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return s.mined, nil
+}
+
+func (s *fakeRawNonceSource) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return s.pending, nil
+}
+
+func TestCheckRawTransaction(t *testing.T) {
+	parsedABI, err := abi.JSON(strings.NewReader(rawTxTestABI))
+	if err != nil {
+		t.Fatalf("abi.JSON: %v", err)
+	}
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(1337)
+	contractAddress := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	otherAddress := common.HexToAddress("0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512")
+
+	pack := func(method string, args ...interface{}) []byte {
+		data, err := parsedABI.Pack(method, args...)
+		if err != nil {
+			t.Fatalf("Pack(%s): %v", method, err)
+		}
+		return data
+	}
+	setData := pack("set", big.NewInt(42))
+
+	// dynamicTx monta uma transação EIP-1559 válida para set(42); edit altera os campos antes da assinatura
+	dynamicTx := func(edit func(tx *types.DynamicFeeTx)) *types.DynamicFeeTx {
+		tx := &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     5,
+			GasTipCap: big.NewInt(1_000_000_000),
+			GasFeeCap: big.NewInt(2_000_000_000),
+			Gas:       50_000,
+			To:        &contractAddress,
+			Value:     big.NewInt(0),
+			Data:      setData,
+		}
+		if edit != nil {
+			edit(tx)
+		}
+		return tx
+	}
+	sign := func(txData types.TxData, signer types.Signer, key *ecdsa.PrivateKey) *types.Transaction {
+		tx, err := types.SignNewTx(key, signer, txData)
+		if err != nil {
+			t.Fatalf("SignNewTx: %v", err)
+		}
+		return tx
+	}
+	latest := types.LatestSignerForChainID(chainID)
+	legacyTx := &types.LegacyTx{Nonce: 5, GasPrice: big.NewInt(2_000_000_000), Gas: 50_000, To: &contractAddress, Value: big.NewInt(0), Data: setData}
+	onlySet := func(method string) bool { return method == "set" }
+
+	tests := []struct {
+		name       string
+		tx         *types.Transaction
+		allowed    func(method string) bool
+		nonces     fakeRawNonceSource
+		estimate   uint64
+		wantMethod string
+		wantErr    error
+	}{
+		{name: "transação válida", tx: sign(dynamicTx(nil), latest, key), wantMethod: "set"},
+		{name: "transação legada com EIP-155", tx: sign(legacyTx, latest, key), wantMethod: "set"},
+		{
+			name:       "nonce pendente pode ser substituído",
+			tx:         sign(dynamicTx(func(tx *types.DynamicFeeTx) { tx.Nonce = 4 }), latest, key),
+			nonces:     fakeRawNonceSource{mined: 3, pending: 6},
+			wantMethod: "set",
+		},
+		{
+			name:    "outro destino",
+			tx:      sign(dynamicTx(func(tx *types.DynamicFeeTx) { tx.To = &otherAddress }), latest, key),
+			wantErr: ErrInvalidRawTx,
+		},
+		{name: "sem proteção contra replay", tx: sign(legacyTx, types.HomesteadSigner{}, key), wantErr: ErrChainIDMismatch},
+		{
+			name:    "outra rede",
+			tx:      sign(dynamicTx(func(tx *types.DynamicFeeTx) { tx.ChainID = big.NewInt(1) }), types.LatestSignerForChainID(big.NewInt(1)), key),
+			wantErr: ErrChainIDMismatch,
+		},
+		{
+			name:    "nonce já minerado",
+			tx:      sign(dynamicTx(func(tx *types.DynamicFeeTx) { tx.Nonce = 4 }), latest, key),
+			wantErr: ErrNonceTooLow,
+		},
+		{
+			name:    "nonce com lacuna",
+			tx:      sign(dynamicTx(func(tx *types.DynamicFeeTx) { tx.Nonce = 7 }), latest, key),
+			nonces:  fakeRawNonceSource{mined: 5, pending: 6},
+			wantErr: ErrNonceGap,
+		},
+		{
+			name:    "função fora da lista permitida",
+			tx:      sign(dynamicTx(func(tx *types.DynamicFeeTx) { tx.Data = pack("reset") }), latest, key),
+			wantErr: ErrMethodNotAllowed,
+		},
+		{name: "filtro que recusa todas as funções", tx: sign(dynamicTx(nil), latest, key), allowed: func(string) bool { return false }, wantErr: ErrMethodNotAllowed},
+		{
+			name:    "função somente leitura",
+			tx:      sign(dynamicTx(func(tx *types.DynamicFeeTx) { tx.Data = pack("get") }), latest, key),
+			wantErr: ErrMethodNotMutating,
+		},
+		{
+			name:    "seletor desconhecido",
+			tx:      sign(dynamicTx(func(tx *types.DynamicFeeTx) { tx.Data = []byte{0xde, 0xad, 0xbe, 0xef} }), latest, key),
+			wantErr: ErrMethodNotFound,
+		},
+		{
+			name:    "sem seletor",
+			tx:      sign(dynamicTx(func(tx *types.DynamicFeeTx) { tx.Data = nil }), latest, key),
+			wantErr: ErrInvalidRawTx,
+		},
+		{
+			name:    "argumentos truncados",
+			tx:      sign(dynamicTx(func(tx *types.DynamicFeeTx) { tx.Data = setData[:20] }), latest, key),
+			wantErr: ErrInvalidArguments,
+		},
+		{
+			name:    "value em função não payable",
+			tx:      sign(dynamicTx(func(tx *types.DynamicFeeTx) { tx.Value = big.NewInt(1) }), latest, key),
+			wantErr: ErrMethodNotPayable,
+		},
+		{
+			name:     "gas limit abaixo da estimativa",
+			tx:       sign(dynamicTx(nil), latest, key),
+			estimate: 50_001,
+			wantErr:  ErrGasLimitTooLow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed := tt.allowed
+			if allowed == nil {
+				allowed = onlySet
+			}
+			nonces := tt.nonces
+			if nonces == (fakeRawNonceSource{}) {
+				nonces = fakeRawNonceSource{mined: 5, pending: 5}
+			}
+			estimate := tt.estimate
+			if estimate == 0 {
+				estimate = 30_000
+			}
+			gas, err := NewGasEstimator(&fakeGasSource{estimate: estimate}, GasConfig{Multiplier: 1})
+			if err != nil {
+				t.Fatalf("NewGasEstimator: %v", err)
+			}
+			sc := &SmartContract{name: "SimpleStorage", contractAddress: contractAddress, parsedABI: parsedABI, chainID: chainID, gas: gas}
+
+			sender, method, err := sc.checkRawTransaction(context.Background(), tt.tx, allowed, &nonces)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("esperado %v, recebido %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkRawTransaction: %v", err)
+			}
+			if sender != from {
+				t.Errorf("remetente %s, esperado %s", sender.Hex(), from.Hex())
+			}
+			if method.Name != tt.wantMethod {
+				t.Errorf("função %s, esperado %s", method.Name, tt.wantMethod)
+			}
+		})
+	}
+
+	// sem filtro nenhuma função é aceita
+	sc := &SmartContract{name: "SimpleStorage", contractAddress: contractAddress, parsedABI: parsedABI, chainID: chainID}
+	if _, _, err := sc.checkRawTransaction(context.Background(), sign(dynamicTx(nil), latest, key), nil, &fakeRawNonceSource{}); !errors.Is(err, ErrMethodNotAllowed) {
+		t.Errorf("filtro nulo: esperado ErrMethodNotAllowed, recebido %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RecoveryReport resume o resultado da recuperação das transações abertas na inicialização
+type RecoveryReport struct {
+	Open          int // transações não finais encontradas no journal
+	Reconciled    int // já conhecidas pelo nó (no mempool ou mineradas)
+	Rebroadcasted int // reenviadas ao nó
+	Dropped       int // com o nonce já usado por outra transação
+	Failed        int // não puderam ser reconciliadas nem reenviadas
+}
+
+// Recover carrega do journal as transações que não atingiram um estado final e as reconcilia com o nó:
+// as que o nó conhece voltam a ser acompanhadas, as que ele esqueceu são reenviadas a partir dos bytes assinados
+// e as que tiveram o nonce consumido por outra transação são marcadas como descartadas.
+func (m *TxMonitor) Recover(ctx context.Context) (RecoveryReport, error) {
+	var report RecoveryReport
+
+	records, err := m.tracker.journal.ListOpenTransactions(ctx)
+	if err != nil {
+		return report, fmt.Errorf("erro ao listar transações abertas no journal: %w", err)
+	}
+	report.Open = len(records)
+
+	for _, record := range records {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(record.Raw); err != nil {
+			fmt.Printf("Erro ao decodificar transação %s do journal: %v\n", record.Hash.Hex(), err)
+			report.Failed++
+			continue
+		}
+		m.tracker.restore(tx, record)
+
+		known, err := m.knownByNode(ctx, tx)
+		if err != nil {
+			fmt.Printf("Erro ao consultar transação %s no nó: %v\n", record.Hash.Hex(), err)
+			report.Failed++
+			continue
+		}
+
+		switch {
+		case known:
+			report.Reconciled++
+		case record.State == TxStateReplaced:
+			// a substituta é recuperada por conta própria; a original não deve voltar ao mempool
+			report.Reconciled++
+		default:
+			if err := m.client.SendTransaction(ctx, tx); err != nil && !isAlreadyKnownError(err) {
+				if isNonceError(err) {
+					m.tracker.update(ctx, TxStatus{Hash: record.Hash, State: TxStateDropped})
+					report.Dropped++
+					continue
+				}
+				fmt.Printf("Erro ao reenviar transação %s: %v\n", record.Hash.Hex(), err)
+				report.Failed++
+				continue
+			}
+			m.tracker.rebroadcasted(record.Hash)
+			report.Rebroadcasted++
+		}
+
+		if _, err := m.TransactionStatus(ctx, record.Hash); err != nil {
+			fmt.Printf("Erro ao atualizar estado da transação %s: %v\n", record.Hash.Hex(), err)
+		}
+		go m.watchTransaction(record.Hash)
+	}
+
+	return report, nil
+}
+
+// knownByNode indica se o nó tem a transação no mempool ou já a minerou
+func (m *TxMonitor) knownByNode(ctx context.Context, tx *types.Transaction) (bool, error) {
+	receipt, err := m.client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return false, err
+	}
+	if receipt != nil {
+		return true, nil
+	}
+
+	found, _, err := m.client.TransactionByHash(ctx, tx.Hash())
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return false, err
+	}
+	return found != nil, nil
+}
+
+// isAlreadyKnownError indica que o nó já tinha a transação reenviada
+func isAlreadyKnownError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
@@ -0,0 +1,206 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Registry guarda todos os contratos implantados pelo Hardhat Ignition, indexados pelo nome
+type Registry struct {
+	client        *RPCPool
+	subscriptions *SubscriptionManager
+	monitor       *TxMonitor
+	contracts     map[string]*SmartContract
+	names         []string
+}
+
+// NewRegistry conecta aos nós do pool RPC e carrega cada entrada do deployed_addresses.json do Ignition junto com o seu artefato.
+// O ABI é procurado primeiro em <diretório do deployment>/artifacts/<id>.json e depois em <artifactsDir>/<Nome>.sol/<Nome>.json.
+func NewRegistry(poolConfig RPCPoolConfig, addressesPath, artifactsDir string, tracker *TxTracker, feeConfig FeeConfig, gasConfig GasConfig) (*Registry, error) {
+	if tracker == nil {
+		return nil, fmt.Errorf("rastreador de transações não pode ser nulo")
+	}
+
+	addressData, err := os.ReadFile(addressesPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro lendo endereços dos contratos: %w", err)
+	}
+
+	var deploymentMap map[string]string
+	if err := json.Unmarshal(addressData, &deploymentMap); err != nil {
+		return nil, fmt.Errorf("erro lendo JSON de endereços dos contratos: %w", err)
+	}
+	if len(deploymentMap) == 0 {
+		return nil, fmt.Errorf("nenhum contrato encontrado em %s", addressesPath)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := NewRPCPool(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("erro conectando aos nós Besu: %w", err)
+	}
+	// o pool mantém uma goroutine de verificação de saúde; ela é encerrada se o Registry não for criado
+	loaded := false
+	defer func() {
+		if !loaded {
+			client.Close()
+		}
+	}()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter Chain ID da rede: %w", err)
+	}
+
+	subscriptions, err := NewSubscriptionManager(client, poolConfig.HeadPollInterval, poolConfig.ReconnectMaxBackoff)
+	if err != nil {
+		return nil, err
+	}
+
+	// A mesma chave assina para todos os contratos, então a sequência de nonces precisa ser única
+	monitor, err := NewTxMonitor(client, tracker, NewNonceManager(client), subscriptions)
+	if err != nil {
+		return nil, err
+	}
+	fees, err := NewFeeOracle(client, feeConfig)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := NewGasEstimator(client, gasConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	deploymentIDs := make([]string, 0, len(deploymentMap))
+	for id := range deploymentMap {
+		deploymentIDs = append(deploymentIDs, id)
+	}
+	sort.Strings(deploymentIDs)
+	names := contractNames(deploymentIDs)
+
+	registry := &Registry{
+		client:        client,
+		subscriptions: subscriptions,
+		monitor:       monitor,
+		contracts:     make(map[string]*SmartContract, len(deploymentIDs)),
+	}
+	deploymentDir := filepath.Dir(addressesPath)
+	for _, id := range deploymentIDs {
+		address := deploymentMap[id]
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("endereço inválido para o contrato %s: %s", id, address)
+		}
+
+		parsedABI, err := loadArtifactABI(deploymentDir, artifactsDir, id)
+		if err != nil {
+			return nil, err
+		}
+
+		name := names[id]
+		contract, err := NewSmartContract(name, monitor, chainID, common.HexToAddress(address), parsedABI, fees, gas)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao inicializar contrato %s: %w", id, err)
+		}
+		registry.contracts[name] = contract
+		registry.names = append(registry.names, name)
+		fmt.Printf("Contrato %s (%s) carregado no endereço %s\n", name, id, address)
+	}
+
+	subscriptions.Start()
+	loaded = true
+	return registry, nil
+}
+
+// Get retorna o contrato registrado com o nome informado
+func (r *Registry) Get(name string) (*SmartContract, bool) {
+	contract, ok := r.contracts[name]
+	return contract, ok
+}
+
+// Names retorna os nomes dos contratos registrados em ordem alfabética do ID de deployment
+func (r *Registry) Names() []string {
+	return append([]string(nil), r.names...)
+}
+
+// RecoverTransactions reconcilia com o nó as transações que ficaram abertas no journal (ver TxMonitor.Recover)
+func (r *Registry) RecoverTransactions(ctx context.Context) (RecoveryReport, error) {
+	return r.monitor.Recover(ctx)
+}
+
+// Close encerra as conexões com os nós
+func (r *Registry) Close() {
+	r.subscriptions.Stop()
+	r.client.Close()
+}
+
+// contractNames define o nome de cada contrato a partir do ID do Ignition (Modulo#Contrato).
+// O nome é o do contrato; se dois módulos implantarem contratos com o mesmo nome, ambos passam a usar Modulo.Contrato.
+func contractNames(deploymentIDs []string) map[string]string {
+	count := make(map[string]int)
+	for _, id := range deploymentIDs {
+		count[contractNameFromID(id)]++
+	}
+
+	names := make(map[string]string, len(deploymentIDs))
+	for _, id := range deploymentIDs {
+		name := contractNameFromID(id)
+		if count[name] > 1 {
+			name = strings.ReplaceAll(id, "#", ".")
+		}
+		names[id] = name
+	}
+	return names
+}
+
+func contractNameFromID(id string) string {
+	if _, name, found := strings.Cut(id, "#"); found {
+		return name
+	}
+	return id
+}
+
+// loadArtifactABI carrega o ABI do artefato de um contrato implantado
+func loadArtifactABI(deploymentDir, artifactsDir, deploymentID string) (abi.ABI, error) {
+	contractName := contractNameFromID(deploymentID)
+	candidates := []string{
+		filepath.Join(deploymentDir, "artifacts", deploymentID+".json"),
+		filepath.Join(artifactsDir, contractName+".sol", contractName+".json"),
+	}
+
+	for _, candidate := range candidates {
+		data, err := os.ReadFile(candidate)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return abi.ABI{}, fmt.Errorf("erro lendo artefato %s: %w", candidate, err)
+		}
+
+		var artifact struct {
+			ABI json.RawMessage `json:"abi"`
+		}
+		if err := json.Unmarshal(data, &artifact); err != nil {
+			return abi.ABI{}, fmt.Errorf("erro parseando artefato %s: %w", candidate, err)
+		}
+
+		parsedABI, err := abi.JSON(strings.NewReader(string(artifact.ABI)))
+		if err != nil {
+			return abi.ABI{}, fmt.Errorf("erro convertendo ABI do artefato %s: %w", candidate, err)
+		}
+		return parsedABI, nil
+	}
+
+	return abi.ABI{}, fmt.Errorf("artefato do contrato %s não encontrado (procurado em %s)", deploymentID, strings.Join(candidates, ", "))
+}
@@ -0,0 +1,105 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/vmm2136/besu_challenge/go-app/internal/pkg/ethutils"
+)
+
+// cancelGasLimit é o gás de uma transferência simples, usada para cancelar uma transação
+const cancelGasLimit = 21000
+
+// ErrTxNotReplaceable indica que a transação não pode mais ser substituída (já minerada, final ou de outra conta)
+var ErrTxNotReplaceable = errors.New("transação não pode ser substituída")
+
+// SpeedUpTransaction reenvia a transação com o mesmo nonce, destino, valor e dados, mas com taxas maiores
+func (sc *SmartContract) SpeedUpTransaction(ctx context.Context, hash common.Hash, signer ethutils.Signer) (common.Hash, error) {
+	return sc.replaceTransaction(ctx, hash, signer, "speedup", func(original *types.Transaction, from common.Address) (*common.Address, *big.Int, []byte, uint64) {
+		return original.To(), original.Value(), original.Data(), original.Gas()
+	})
+}
+
+// CancelTransaction substitui a transação por uma transferência de valor zero da conta para ela mesma,
+// com o mesmo nonce e taxas maiores, para que a original nunca seja executada
+func (sc *SmartContract) CancelTransaction(ctx context.Context, hash common.Hash, signer ethutils.Signer) (common.Hash, error) {
+	return sc.replaceTransaction(ctx, hash, signer, "cancel", func(original *types.Transaction, from common.Address) (*common.Address, *big.Int, []byte, uint64) {
+		return &from, big.NewInt(0), nil, cancelGasLimit
+	})
+}
+
+// replacementPayload define destino, valor, dados e gás da transação substituta a partir da original
+type replacementPayload func(original *types.Transaction, from common.Address) (to *common.Address, value *big.Int, data []byte, gas uint64)
+
+// replaceTransaction assina e envia uma transação com o mesmo nonce da original, com taxas acima do aumento
+// mínimo exigido pelo nó. Só transações enviadas pela API e ainda não mineradas podem ser substituídas.
+func (sc *SmartContract) replaceTransaction(ctx context.Context, hash common.Hash, signer ethutils.Signer, method string, payload replacementPayload) (common.Hash, error) {
+	original, ok := sc.tracker.Transaction(hash)
+	if !ok {
+		return common.Hash{}, ErrTxNotFound
+	}
+
+	status, err := sc.TransactionStatus(ctx, hash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if status.State != TxStateSubmitted && status.State != TxStatePending {
+		return common.Hash{}, fmt.Errorf("%w: estado atual é '%s'", ErrTxNotReplaceable, status.State)
+	}
+
+	fromAddress := signer.Address()
+	if fromAddress != status.From {
+		return common.Hash{}, fmt.Errorf("%w: enviada por %s, chave atual é de %s", ErrTxNotReplaceable, status.From.Hex(), fromAddress.Hex())
+	}
+
+	fees, err := sc.fees.ReplacementFees(ctx, original)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	to, value, data, gas := payload(original, fromAddress)
+	var txData types.TxData
+	if fees.IsLegacy() {
+		txData = &types.LegacyTx{
+			Nonce:    original.Nonce(),
+			GasPrice: fees.GasPrice,
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		}
+	} else {
+		txData = &types.DynamicFeeTx{
+			ChainID:   sc.chainID,
+			Nonce:     original.Nonce(),
+			GasTipCap: fees.GasTipCap,
+			GasFeeCap: fees.GasFeeCap,
+			Gas:       gas,
+			To:        to,
+			Value:     value,
+			Data:      data,
+		}
+	}
+
+	signedTx, err := signer.SignTx(ctx, types.NewTx(txData), sc.chainID)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("erro ao assinar transação substituta: %w", err)
+	}
+	if err := sc.tracker.Prepare(ctx, signedTx, fromAddress, method); err != nil {
+		return common.Hash{}, err
+	}
+	if err := sc.client.SendTransaction(ctx, signedTx); err != nil {
+		sc.tracker.fail(ctx, signedTx.Hash())
+		return common.Hash{}, fmt.Errorf("erro ao enviar transação substituta de %s: %w", hash.Hex(), err)
+	}
+
+	sc.tracker.Track(signedTx, fromAddress)
+	sc.tracker.markReplaced(ctx, hash, signedTx.Hash())
+	go sc.watchTransaction(signedTx.Hash())
+
+	return signedTx.Hash(), nil
+}
@@ -0,0 +1,179 @@
+package contract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrExecutionReverted é o erro base de todas as falhas em que o contrato rejeitou a chamada.
+// Os tipos RevertError, PanicError e CustomError o retornam em Unwrap, então errors.Is(err, ErrExecutionReverted)
+// distingue "o contrato recusou" de falhas de comunicação com o nó.
+var ErrExecutionReverted = errors.New("execução revertida pelo contrato")
+
+// Seletores dos erros padrão do Solidity
+var (
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+)
+
+// panicDescriptions descreve os códigos de Panic(uint256) emitidos pelo compilador Solidity
+var panicDescriptions = map[uint64]string{
+	0x00: "panic genérico do compilador",
+	0x01: "assert falhou",
+	0x11: "overflow ou underflow aritmético",
+	0x12: "divisão ou módulo por zero",
+	0x21: "conversão para enum inválida",
+	0x22: "storage byte array codificado incorretamente",
+	0x31: "pop() em array vazio",
+	0x32: "acesso a array fora dos limites",
+	0x41: "alocação excessiva de memória",
+	0x51: "chamada a função interna não inicializada",
+}
+
+// RevertError é um revert com Error(string) (require/revert com mensagem) ou sem dados decodificáveis
+type RevertError struct {
+	Reason string // mensagem de require/revert, quando o contrato a informa
+	Data   []byte // dados brutos do revert retornados pelo nó
+}
+
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return ErrExecutionReverted.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrExecutionReverted.Error(), e.Reason)
+}
+
+func (e *RevertError) Unwrap() error {
+	return ErrExecutionReverted
+}
+
+// PanicError é um revert com Panic(uint256), emitido pelo compilador em falhas como overflow ou assert
+type PanicError struct {
+	Code        *big.Int
+	Description string
+	Data        []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: panic 0x%x (%s)", ErrExecutionReverted.Error(), e.Code, e.Description)
+}
+
+func (e *PanicError) Unwrap() error {
+	return ErrExecutionReverted
+}
+
+// CustomError é um revert com um erro customizado declarado no ABI do contrato (error Nome(args))
+type CustomError struct {
+	Name      string
+	Signature string
+	Args      []MethodOutput
+	Data      []byte
+}
+
+func (e *CustomError) Error() string {
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = fmt.Sprint(arg.Value)
+	}
+	return fmt.Sprintf("%s: %s(%s)", ErrExecutionReverted.Error(), e.Name, strings.Join(args, ", "))
+}
+
+func (e *CustomError) Unwrap() error {
+	return ErrExecutionReverted
+}
+
+// executionReverted é a mensagem de erro dos nós para reverts ("execution reverted" no Geth, "Execution reverted" no Besu)
+var executionReverted = []string{"execution reverted", "Execution reverted"}
+
+// revertFromRPCError identifica um revert em um erro retornado pelo nó. Só mensagens com "execution reverted" são
+// reverts; os dados do revert vêm do campo data do erro JSON-RPC (o Besu os inclui com --revert-reason-enabled).
+func revertFromRPCError(err error) (*RevertError, bool) {
+	message := err.Error()
+	var revertErr *RevertError
+	for _, marker := range executionReverted {
+		if index := strings.Index(message, marker); index >= 0 {
+			revertErr = &RevertError{}
+			if reason, found := strings.CutPrefix(message[index+len(marker):], ": "); found {
+				revertErr.Reason = reason
+			}
+			break
+		}
+	}
+	if revertErr == nil {
+		return nil, false
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if hexData, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(hexData); decodeErr == nil && len(data) > 0 {
+				revertErr.Data = data
+			}
+		}
+	}
+	return revertErr, true
+}
+
+// decodeRevert converte err em RevertError, PanicError ou CustomError quando ele representa um revert,
+// decodificando os dados contra o ABI do contrato. Outros erros são retornados sem alteração.
+func decodeRevert(parsedABI abi.ABI, err error) error {
+	var revertErr *RevertError
+	if !errors.As(err, &revertErr) {
+		if revertErr, ok := revertFromRPCError(err); ok {
+			return decodeRevertData(parsedABI, revertErr)
+		}
+		return err
+	}
+	return decodeRevertData(parsedABI, revertErr)
+}
+
+func decodeRevertData(parsedABI abi.ABI, revertErr *RevertError) error {
+	data := revertErr.Data
+	if len(data) < 4 {
+		return revertErr
+	}
+
+	selector := data[:4]
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			return &RevertError{Reason: reason, Data: data}
+		}
+		return revertErr
+
+	case bytes.Equal(selector, panicSelector):
+		if len(data) < 36 {
+			return revertErr
+		}
+		code := new(big.Int).SetBytes(data[4:36])
+		description, ok := panicDescriptions[code.Uint64()]
+		if !ok || !code.IsUint64() {
+			description = "código de panic desconhecido"
+		}
+		return &PanicError{Code: code, Description: description, Data: data}
+	}
+
+	for _, abiErr := range parsedABI.Errors {
+		if !bytes.Equal(abiErr.ID[:4], selector) {
+			continue
+		}
+		values, err := abiErr.Inputs.Unpack(data[4:])
+		if err != nil {
+			return revertErr
+		}
+		return &CustomError{
+			Name:      abiErr.Name,
+			Signature: abiErr.Sig,
+			Args:      encodeOutputs(abiErr.Inputs, values),
+			Data:      data,
+		}
+	}
+	return revertErr
+}
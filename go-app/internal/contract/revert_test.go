@@ -0,0 +1,132 @@
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const revertTestABI = `[{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}]`
+
+// rpcTestError imita um erro JSON-RPC do nó com o campo data
+type rpcTestError struct {
+	message string
+	data    interface{}
+}
+
+func (e rpcTestError) Error() string          { return e.message }
+func (e rpcTestError) ErrorCode() int         { return 3 }
+func (e rpcTestError) ErrorData() interface{} { return e.data }
+
+func TestDecodeRevert(t *testing.T) {
+	parsedABI, err := abi.JSON(strings.NewReader(revertTestABI))
+	if err != nil {
+		t.Fatalf("abi.JSON: %v", err)
+	}
+
+	stringType, _ := abi.NewType("string", "", nil)
+	reason, _ := abi.Arguments{{Type: stringType}}.Pack("saldo insuficiente")
+	errorData := hexutil.Encode(append(append([]byte{}, errorSelector...), reason...))
+
+	panicData := func(code int64) string {
+		return hexutil.Encode(append(append([]byte{}, panicSelector...), big.NewInt(code).FillBytes(make([]byte, 32))...))
+	}
+
+	customErr := parsedABI.Errors["InsufficientBalance"]
+	customArgs, _ := customErr.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+	customData := hexutil.Encode(append(append([]byte{}, customErr.ID[:4]...), customArgs...))
+
+	tests := []struct {
+		name       string
+		err        error
+		want       string
+		wantRevert bool
+	}{
+		{
+			name:       "Error(string) nos dados",
+			err:        rpcTestError{"execution reverted", errorData},
+			want:       "execução revertida pelo contrato: saldo insuficiente",
+			wantRevert: true,
+		},
+		{
+			name:       "Error(string) no Besu",
+			err:        rpcTestError{"Execution reverted", errorData},
+			want:       "execução revertida pelo contrato: saldo insuficiente",
+			wantRevert: true,
+		},
+		{
+			name:       "motivo só na mensagem",
+			err:        errors.New("execution reverted: saldo insuficiente"),
+			want:       "execução revertida pelo contrato: saldo insuficiente",
+			wantRevert: true,
+		},
+		{
+			name:       "revert sem dados",
+			err:        errors.New("execution reverted"),
+			want:       "execução revertida pelo contrato",
+			wantRevert: true,
+		},
+		{
+			name:       "Panic(uint256) de overflow",
+			err:        rpcTestError{"execution reverted", panicData(0x11)},
+			want:       "execução revertida pelo contrato: panic 0x11 (overflow ou underflow aritmético)",
+			wantRevert: true,
+		},
+		{
+			name:       "Panic(uint256) desconhecido",
+			err:        rpcTestError{"execution reverted", panicData(0x99)},
+			want:       "execução revertida pelo contrato: panic 0x99 (código de panic desconhecido)",
+			wantRevert: true,
+		},
+		{
+			name:       "erro customizado",
+			err:        rpcTestError{"execution reverted", customData},
+			want:       "execução revertida pelo contrato: InsufficientBalance(1, 2)",
+			wantRevert: true,
+		},
+		{
+			name:       "seletor desconhecido",
+			err:        rpcTestError{"execution reverted", "0xdeadbeef"},
+			want:       "execução revertida pelo contrato",
+			wantRevert: true,
+		},
+		{
+			name:       "RevertError já construído",
+			err:        fmt.Errorf("erro ao simular: %w", &RevertError{Data: hexutil.MustDecode(customData)}),
+			want:       "execução revertida pelo contrato: InsufficientBalance(1, 2)",
+			wantRevert: true,
+		},
+		{
+			name: "erro com dados que não é revert",
+			err:  rpcTestError{"replacement transaction underpriced", errorData},
+			want: "replacement transaction underpriced",
+		},
+		{
+			name: "mensagem que só cita revert",
+			err:  errors.New("transaction reverted to snapshot"),
+			want: "transaction reverted to snapshot",
+		},
+		{
+			name: "falha de comunicação",
+			err:  errors.New("connection refused"),
+			want: "connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeRevert(parsedABI, tt.err)
+			if got.Error() != tt.want {
+				t.Errorf("esperado %q, recebido %q", tt.want, got.Error())
+			}
+			if errors.Is(got, ErrExecutionReverted) != tt.wantRevert {
+				t.Errorf("errors.Is(ErrExecutionReverted) = %v, esperado %v", !tt.wantRevert, tt.wantRevert)
+			}
+		})
+	}
+}
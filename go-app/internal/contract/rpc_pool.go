@@ -0,0 +1,511 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// ErrNoRPCNode indica que nenhum nó do pool respondeu
+	ErrNoRPCNode = errors.New("nenhum nó RPC disponível")
+	// ErrSubscriptionsUnsupported indica que nenhum endpoint do pool é WebSocket ou IPC
+	ErrSubscriptionsUnsupported = errors.New("nenhum endpoint RPC suporta assinaturas (use ws://, wss:// ou IPC)")
+)
+
+// RPCPoolConfig configura o pool de nós RPC
+type RPCPoolConfig struct {
+	URLs           []string      // http(s)://, ws(s):// ou caminho IPC; o primeiro é o primário, que recebe as escritas
+	HealthInterval time.Duration // intervalo entre as verificações de saúde
+	MaxBlockLag    uint64        // blocos que um nó pode estar atrás do melhor nó antes de ser afastado
+	MinPeers       uint64        // peers mínimos para um nó ser considerado saudável
+
+	HeadPollInterval    time.Duration // consulta de novos blocos quando nenhum endpoint aceita assinaturas
+	ReconnectMaxBackoff time.Duration // maior espera entre tentativas de reabrir uma assinatura
+}
+
+// rpcNode é um nó do pool com o resultado da última verificação
+type rpcNode struct {
+	url string
+
+	mu        sync.RWMutex
+	client    *ethclient.Client // nil enquanto a conexão (WebSocket ou IPC) não puder ser aberta
+	reachable bool              // respondeu à última verificação
+	healthy   bool              // respondeu, está próximo do melhor head e tem peers suficientes
+	head      uint64
+	peers     uint64
+	lastErr   error
+	checkedAt time.Time
+}
+
+// RPCPool distribui as chamadas ao nó entre vários endpoints. Leituras são balanceadas entre os nós saudáveis;
+// envios de transação e consultas ao mempool (nonce pendente, transação pendente) vão ao primário, o primeiro nó
+// saudável na ordem configurada. Em erro de conexão a chamada é repetida no próximo nó e o nó que falhou é
+// afastado até a próxima verificação de saúde.
+type RPCPool struct {
+	config RPCPoolConfig
+	nodes  []*rpcNode
+	next   atomic.Uint64 // rodízio das leituras
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRPCPool faz a primeira verificação de saúde dos endpoints e inicia as verificações periódicas.
+// Endpoints WebSocket e IPC que não puderem ser conectados agora são reconectados nas verificações seguintes.
+func NewRPCPool(ctx context.Context, config RPCPoolConfig) (*RPCPool, error) {
+	if len(config.URLs) == 0 {
+		return nil, fmt.Errorf("nenhum endpoint RPC configurado")
+	}
+	if config.HealthInterval <= 0 {
+		return nil, fmt.Errorf("intervalo de verificação dos nós RPC deve ser positivo")
+	}
+
+	pool := &RPCPool{config: config}
+	for _, url := range config.URLs {
+		pool.nodes = append(pool.nodes, &rpcNode{url: url})
+	}
+
+	pool.checkHealth(ctx)
+	if len(pool.reachableNodes()) == 0 {
+		pool.closeClients()
+		return nil, fmt.Errorf("%w: nenhum dos %d endpoints respondeu", ErrNoRPCNode, len(pool.nodes))
+	}
+
+	healthCtx, cancel := context.WithCancel(context.Background())
+	pool.cancel = cancel
+	pool.done = make(chan struct{})
+	go pool.runHealthChecks(healthCtx)
+	return pool, nil
+}
+
+// Close interrompe as verificações de saúde e fecha as conexões
+func (p *RPCPool) Close() {
+	if p.cancel != nil {
+		p.cancel()
+		<-p.done
+	}
+	p.closeClients()
+}
+
+func (p *RPCPool) closeClients() {
+	for _, node := range p.nodes {
+		if client := node.conn(); client != nil {
+			client.Close()
+		}
+	}
+}
+
+// conn retorna a conexão do nó, ou nil se ela ainda não foi aberta
+func (n *rpcNode) conn() *ethclient.Client {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.client
+}
+
+// dial abre a conexão do nó se ela ainda não existir
+func (n *rpcNode) dial(ctx context.Context) (*ethclient.Client, error) {
+	if client := n.conn(); client != nil {
+		return client, nil
+	}
+	client, err := ethclient.DialContext(ctx, n.url)
+	if err != nil {
+		return nil, fmt.Errorf("erro conectando: %w", err)
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.client != nil {
+		client.Close()
+		return n.client, nil
+	}
+	n.client = client
+	return client, nil
+}
+
+// SupportsSubscriptions indica se algum endpoint do pool é WebSocket ou IPC
+func (p *RPCPool) SupportsSubscriptions() bool {
+	for _, node := range p.nodes {
+		if supportsSubscriptions(node.url) {
+			return true
+		}
+	}
+	return false
+}
+
+// supportsSubscriptions indica se o endpoint aceita assinaturas: WebSocket ou caminho de socket IPC
+func supportsSubscriptions(url string) bool {
+	lower := strings.ToLower(url)
+	if strings.HasPrefix(lower, "ws://") || strings.HasPrefix(lower, "wss://") {
+		return true
+	}
+	return !strings.Contains(lower, "://")
+}
+
+func (p *RPCPool) runHealthChecks(ctx context.Context) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.config.HealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.checkHealth(ctx)
+		}
+	}
+}
+
+// checkHealth consulta altura e peers de todos os nós em paralelo e afasta os que não responderam,
+// os que estão mais de MaxBlockLag blocos atrás do melhor nó e os que têm menos de MinPeers peers
+func (p *RPCPool) checkHealth(ctx context.Context) {
+	type probe struct {
+		head  uint64
+		peers uint64
+		err   error
+	}
+	probes := make([]probe, len(p.nodes))
+
+	var wg sync.WaitGroup
+	for i, node := range p.nodes {
+		wg.Add(1)
+		go func(i int, node *rpcNode) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, p.config.HealthInterval)
+			defer cancel()
+
+			client, err := node.dial(checkCtx)
+			if err != nil {
+				probes[i].err = err
+				return
+			}
+			head, err := client.BlockNumber(checkCtx)
+			if err != nil {
+				probes[i].err = fmt.Errorf("erro ao obter último bloco: %w", err)
+				return
+			}
+			peers, err := client.PeerCount(checkCtx)
+			if err != nil {
+				probes[i].err = fmt.Errorf("erro ao obter número de peers: %w", err)
+				return
+			}
+			probes[i] = probe{head: head, peers: peers}
+		}(i, node)
+	}
+	wg.Wait()
+
+	var bestHead uint64
+	for _, probe := range probes {
+		if probe.err == nil && probe.head > bestHead {
+			bestHead = probe.head
+		}
+	}
+
+	now := time.Now()
+	for i, node := range p.nodes {
+		probe := probes[i]
+		healthy := probe.err == nil
+		lastErr := probe.err
+		switch {
+		case !healthy:
+		case probe.head+p.config.MaxBlockLag < bestHead:
+			healthy = false
+			lastErr = fmt.Errorf("%d blocos atrás do melhor nó (%d)", bestHead-probe.head, bestHead)
+		case probe.peers < p.config.MinPeers:
+			healthy = false
+			lastErr = fmt.Errorf("%d peer(s), mínimo %d", probe.peers, p.config.MinPeers)
+		}
+
+		node.mu.Lock()
+		wasHealthy := node.healthy || node.checkedAt.IsZero()
+		node.reachable = probe.err == nil
+		node.healthy = healthy
+		node.head = probe.head
+		node.peers = probe.peers
+		node.lastErr = lastErr
+		node.checkedAt = now
+		node.mu.Unlock()
+
+		switch {
+		case wasHealthy && !healthy:
+			fmt.Printf("Nó RPC %s afastado: %v\n", node.url, lastErr)
+		case !wasHealthy && healthy:
+			fmt.Printf("Nó RPC %s saudável novamente (bloco %d, %d peers)\n", node.url, probe.head, probe.peers)
+		}
+	}
+}
+
+// markFailed afasta um nó que falhou em uma chamada, até a próxima verificação de saúde
+func (p *RPCPool) markFailed(node *rpcNode, err error) {
+	node.mu.Lock()
+	wasHealthy := node.healthy
+	node.healthy = false
+	node.reachable = false
+	node.lastErr = err
+	node.mu.Unlock()
+	if wasHealthy {
+		fmt.Printf("Nó RPC %s afastado após erro de conexão: %v\n", node.url, err)
+	}
+}
+
+func (p *RPCPool) healthyNodes() []*rpcNode {
+	return p.filterNodes(func(node *rpcNode) bool { return node.healthy })
+}
+
+func (p *RPCPool) reachableNodes() []*rpcNode {
+	return p.filterNodes(func(node *rpcNode) bool { return node.reachable })
+}
+
+func (p *RPCPool) filterNodes(keep func(*rpcNode) bool) []*rpcNode {
+	var nodes []*rpcNode
+	for _, node := range p.nodes {
+		node.mu.RLock()
+		ok := keep(node)
+		node.mu.RUnlock()
+		if ok {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// candidates retorna os nós a tentar, em ordem: os saudáveis e, se nenhum estiver, os que ainda respondem
+// (por exemplo, um único nó sem peers) e, por último, todos
+func (p *RPCPool) candidates() []*rpcNode {
+	if nodes := p.healthyNodes(); len(nodes) > 0 {
+		return nodes
+	}
+	if nodes := p.reachableNodes(); len(nodes) > 0 {
+		return nodes
+	}
+	return p.nodes
+}
+
+// read executa uma leitura começando pelo próximo nó do rodízio
+func (p *RPCPool) read(ctx context.Context, call func(*ethclient.Client) error) error {
+	nodes := p.candidates()
+	start := int(p.next.Add(1) % uint64(len(nodes)))
+	rotated := make([]*rpcNode, 0, len(nodes))
+	rotated = append(rotated, nodes[start:]...)
+	rotated = append(rotated, nodes[:start]...)
+	return p.try(ctx, rotated, call)
+}
+
+// primary executa uma chamada no primário, passando para o próximo nó na ordem configurada em erro de conexão
+func (p *RPCPool) primary(ctx context.Context, call func(*ethclient.Client) error) error {
+	return p.try(ctx, p.candidates(), call)
+}
+
+// subscribe abre uma assinatura no primeiro nó WebSocket ou IPC disponível, na ordem configurada
+func (p *RPCPool) subscribe(ctx context.Context, call func(*ethclient.Client) error) error {
+	var nodes []*rpcNode
+	for _, node := range p.candidates() {
+		if supportsSubscriptions(node.url) {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		if !p.SupportsSubscriptions() {
+			return ErrSubscriptionsUnsupported
+		}
+		// os nós com assinatura estão afastados; tenta mesmo assim, na ordem configurada
+		for _, node := range p.nodes {
+			if supportsSubscriptions(node.url) {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	return p.try(ctx, nodes, call)
+}
+
+func (p *RPCPool) try(ctx context.Context, nodes []*rpcNode, call func(*ethclient.Client) error) error {
+	lastErr := errors.New("nenhum nó conectado")
+	for _, node := range nodes {
+		client := node.conn()
+		if client == nil {
+			continue
+		}
+		err := call(client)
+		if err == nil || !isConnectionError(ctx, err) {
+			return err
+		}
+		p.markFailed(node, err)
+		lastErr = fmt.Errorf("nó %s: %w", node.url, err)
+	}
+	return fmt.Errorf("%w: %v", ErrNoRPCNode, lastErr)
+}
+
+// isConnectionError indica uma falha de comunicação com o nó. Respostas de erro do próprio nó (JSON-RPC),
+// "não encontrado" e o cancelamento do contexto de quem chamou não justificam tentar outro nó.
+func isConnectionError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+	var dataErr rpc.DataError
+	return !errors.As(err, &dataErr)
+}
+
+// ChainID retorna o ID da rede
+func (p *RPCPool) ChainID(ctx context.Context) (id *big.Int, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { id, err = c.ChainID(ctx); return err })
+	return id, err
+}
+
+// BlockNumber retorna o número do último bloco
+func (p *RPCPool) BlockNumber(ctx context.Context) (number uint64, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { number, err = c.BlockNumber(ctx); return err })
+	return number, err
+}
+
+// HeaderByNumber retorna o cabeçalho de um bloco pelo número (nil para o último)
+func (p *RPCPool) HeaderByNumber(ctx context.Context, number *big.Int) (header *types.Header, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { header, err = c.HeaderByNumber(ctx, number); return err })
+	return header, err
+}
+
+// HeaderByHash retorna o cabeçalho de um bloco pelo hash
+func (p *RPCPool) HeaderByHash(ctx context.Context, hash common.Hash) (header *types.Header, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { header, err = c.HeaderByHash(ctx, hash); return err })
+	return header, err
+}
+
+// CodeAt retorna o código de uma conta em um bloco
+func (p *RPCPool) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { code, err = c.CodeAt(ctx, account, blockNumber); return err })
+	return code, err
+}
+
+// CodeAtHash retorna o código de uma conta no bloco com o hash informado
+func (p *RPCPool) CodeAtHash(ctx context.Context, account common.Address, blockHash common.Hash) (code []byte, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { code, err = c.CodeAtHash(ctx, account, blockHash); return err })
+	return code, err
+}
+
+// CallContract executa uma chamada somente leitura em um bloco
+func (p *RPCPool) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (out []byte, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { out, err = c.CallContract(ctx, msg, blockNumber); return err })
+	return out, err
+}
+
+// CallContractAtHash executa uma chamada somente leitura no bloco com o hash informado
+func (p *RPCPool) CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) (out []byte, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { out, err = c.CallContractAtHash(ctx, msg, blockHash); return err })
+	return out, err
+}
+
+// BalanceAt retorna o saldo de uma conta em um bloco (nil para o último)
+func (p *RPCPool) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (balance *big.Int, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { balance, err = c.BalanceAt(ctx, account, blockNumber); return err })
+	return balance, err
+}
+
+// FilterLogs busca logs
+func (p *RPCPool) FilterLogs(ctx context.Context, query ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { logs, err = c.FilterLogs(ctx, query); return err })
+	return logs, err
+}
+
+// TransactionReceipt retorna o recibo de uma transação minerada. A consulta vai ao primário, como TransactionByHash:
+// uma réplica atrasada responderia "não encontrado" para uma transação já minerada e ela pareceria descartada.
+func (p *RPCPool) TransactionReceipt(ctx context.Context, hash common.Hash) (receipt *types.Receipt, err error) {
+	err = p.primary(ctx, func(c *ethclient.Client) error { receipt, err = c.TransactionReceipt(ctx, hash); return err })
+	return receipt, err
+}
+
+// FeeHistory retorna o histórico de taxas dos últimos blocos
+func (p *RPCPool) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (history *ethereum.FeeHistory, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error {
+		history, err = c.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+		return err
+	})
+	return history, err
+}
+
+// SuggestGasPrice retorna o gas price sugerido
+func (p *RPCPool) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { price, err = c.SuggestGasPrice(ctx); return err })
+	return price, err
+}
+
+// SuggestGasTipCap retorna a gorjeta sugerida
+func (p *RPCPool) SuggestGasTipCap(ctx context.Context) (tip *big.Int, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { tip, err = c.SuggestGasTipCap(ctx); return err })
+	return tip, err
+}
+
+// EstimateGas estima o gás de uma chamada
+func (p *RPCPool) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (gas uint64, err error) {
+	err = p.read(ctx, func(c *ethclient.Client) error { gas, err = c.EstimateGas(ctx, msg); return err })
+	return gas, err
+}
+
+// PendingCodeAt retorna o código de uma conta no estado pendente do primário
+func (p *RPCPool) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	err = p.primary(ctx, func(c *ethclient.Client) error { code, err = c.PendingCodeAt(ctx, account); return err })
+	return code, err
+}
+
+// PendingCallContract executa uma chamada no estado pendente do primário
+func (p *RPCPool) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) (out []byte, err error) {
+	err = p.primary(ctx, func(c *ethclient.Client) error { out, err = c.PendingCallContract(ctx, msg); return err })
+	return out, err
+}
+
+// PendingNonceAt retorna o próximo nonce da conta segundo o mempool do primário
+func (p *RPCPool) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
+	err = p.primary(ctx, func(c *ethclient.Client) error { nonce, err = c.PendingNonceAt(ctx, account); return err })
+	return nonce, err
+}
+
+// NonceAt retorna o nonce da conta no estado minerado, consultado no primário para ser comparável ao PendingNonceAt
+func (p *RPCPool) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (nonce uint64, err error) {
+	err = p.primary(ctx, func(c *ethclient.Client) error { nonce, err = c.NonceAt(ctx, account, blockNumber); return err })
+	return nonce, err
+}
+
+// TransactionByHash busca uma transação no primário, que conhece as transações pendentes enviadas por ele
+func (p *RPCPool) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	err = p.primary(ctx, func(c *ethclient.Client) error { tx, isPending, err = c.TransactionByHash(ctx, hash); return err })
+	return tx, isPending, err
+}
+
+// SendTransaction envia a transação ao primário. Se ele estiver fora, os mesmos bytes assinados são enviados
+// ao próximo nó. Um nó que já conhece a transação (reenvio, ou propagada antes da falha) conta como envio
+// bem-sucedido: ela já está no mempool.
+func (p *RPCPool) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return p.primary(ctx, func(c *ethclient.Client) error {
+		err := c.SendTransaction(ctx, tx)
+		if err != nil && isAlreadyKnownError(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+// SubscribeNewHead assina os novos cabeçalhos de bloco em um nó WebSocket ou IPC
+func (p *RPCPool) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (sub ethereum.Subscription, err error) {
+	err = p.subscribe(ctx, func(c *ethclient.Client) error { sub, err = c.SubscribeNewHead(ctx, ch); return err })
+	return sub, err
+}
+
+// SubscribeFilterLogs assina logs em um nó WebSocket ou IPC
+func (p *RPCPool) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (sub ethereum.Subscription, err error) {
+	err = p.subscribe(ctx, func(c *ethclient.Client) error { sub, err = c.SubscribeFilterLogs(ctx, query, ch); return err })
+	return sub, err
+}
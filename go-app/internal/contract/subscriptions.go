@@ -0,0 +1,324 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// subscriptionMinBackoff é a primeira espera antes de reabrir uma assinatura que caiu
+	subscriptionMinBackoff = time.Second
+	// maxHeadGap limita quantos cabeçalhos perdidos durante uma desconexão são reenviados aos assinantes
+	maxHeadGap = 256
+)
+
+// SubscriptionManager mantém vivas as assinaturas de novos blocos (newHeads) e de logs nos nós WebSocket ou IPC
+// do pool. Quando uma assinatura cai ela é reaberta com backoff exponencial e os blocos e logs perdidos durante
+// a desconexão são buscados por consulta antes de o fluxo continuar. Sem nenhum endpoint com suporte a assinaturas,
+// os novos blocos são detectados por consulta periódica ao último bloco.
+type SubscriptionManager struct {
+	pool         *RPCPool
+	pollInterval time.Duration
+	maxBackoff   time.Duration
+
+	mu    sync.Mutex
+	heads map[chan *types.Header]struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSubscriptionManager cria o gerenciador de assinaturas. pollInterval é o intervalo de consulta do último bloco
+// quando não há assinatura disponível e maxBackoff a maior espera entre tentativas de reconexão.
+func NewSubscriptionManager(pool *RPCPool, pollInterval, maxBackoff time.Duration) (*SubscriptionManager, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("pool RPC não pode ser nulo")
+	}
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("intervalo de consulta de novos blocos deve ser positivo")
+	}
+	if maxBackoff < subscriptionMinBackoff {
+		maxBackoff = subscriptionMinBackoff
+	}
+	return &SubscriptionManager{
+		pool:         pool,
+		pollInterval: pollInterval,
+		maxBackoff:   maxBackoff,
+		heads:        make(map[chan *types.Header]struct{}),
+	}, nil
+}
+
+// Start inicia a assinatura de novos blocos em uma goroutine
+func (s *SubscriptionManager) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.runHeads(ctx)
+}
+
+// Stop encerra a assinatura de novos blocos
+func (s *SubscriptionManager) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// NewHeads registra um assinante dos novos blocos e retorna o canal e a função que cancela o registro.
+// O canal tem buffer pequeno e cabeçalhos são descartados se o assinante estiver ocupado: ele serve para
+// avisar que a cadeia avançou, não para entregar todos os blocos.
+func (s *SubscriptionManager) NewHeads() (<-chan *types.Header, func()) {
+	ch := make(chan *types.Header, 1)
+	s.mu.Lock()
+	s.heads[ch] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.heads, ch)
+			s.mu.Unlock()
+		})
+	}
+}
+
+func (s *SubscriptionManager) publishHead(header *types.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.heads {
+		select {
+		case ch <- header:
+		default:
+		}
+	}
+}
+
+func (s *SubscriptionManager) runHeads(ctx context.Context) {
+	defer close(s.done)
+
+	if !s.pool.SupportsSubscriptions() {
+		fmt.Printf("Nenhum endpoint RPC WebSocket/IPC; novos blocos serão consultados a cada %s\n", s.pollInterval)
+		s.pollHeads(ctx)
+		return
+	}
+
+	var last *types.Header
+	s.keepAlive(ctx, "newHeads", func() (bool, error) {
+		return s.streamHeads(ctx, &last)
+	})
+}
+
+// keepAlive reabre a assinatura sempre que ela cai, com backoff exponencial entre as tentativas.
+// stream retorna quando a assinatura termina e indica se ela chegou a ser aberta.
+func (s *SubscriptionManager) keepAlive(ctx context.Context, name string, stream func() (bool, error)) {
+	backoff := subscriptionMinBackoff
+	for {
+		established, err := stream()
+		if ctx.Err() != nil {
+			return
+		}
+		if established {
+			backoff = subscriptionMinBackoff
+		}
+		fmt.Printf("Assinatura %s interrompida, reconectando em %s: %v\n", name, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if !established {
+			backoff *= 2
+			if backoff > s.maxBackoff {
+				backoff = s.maxBackoff
+			}
+		}
+	}
+}
+
+// streamHeads abre a assinatura newHeads, reenvia os blocos perdidos desde last e repassa os novos cabeçalhos
+func (s *SubscriptionManager) streamHeads(ctx context.Context, last **types.Header) (bool, error) {
+	ch := make(chan *types.Header, 16)
+	sub, err := s.pool.SubscribeNewHead(ctx, ch)
+	if err != nil {
+		return false, err
+	}
+	defer sub.Unsubscribe()
+
+	// cabeçalhos até covered já foram publicados pela busca da lacuna
+	covered, err := s.publishMissedHeads(ctx, last)
+	if err != nil {
+		return true, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case err := <-sub.Err():
+			if err == nil {
+				err = errors.New("assinatura encerrada pelo nó")
+			}
+			return true, err
+		case header := <-ch:
+			if covered > 0 && header.Number.Uint64() <= covered {
+				continue
+			}
+			covered = 0
+			s.publishHead(header)
+			*last = header
+		}
+	}
+}
+
+// publishMissedHeads publica os cabeçalhos entre last e o último bloco e retorna o número do último publicado
+func (s *SubscriptionManager) publishMissedHeads(ctx context.Context, last **types.Header) (uint64, error) {
+	head, err := s.pool.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao obter último bloco: %w", err)
+	}
+	headNumber := head.Number.Uint64()
+
+	from := headNumber
+	if *last != nil {
+		from = (*last).Number.Uint64() + 1
+	}
+	if headNumber >= maxHeadGap && from < headNumber-maxHeadGap {
+		from = headNumber - maxHeadGap
+	}
+
+	for number := from; number < headNumber; number++ {
+		header, err := s.pool.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return 0, fmt.Errorf("erro ao obter bloco %d perdido durante a desconexão: %w", number, err)
+		}
+		s.publishHead(header)
+		*last = header
+	}
+	if *last == nil || (*last).Number.Uint64() < headNumber {
+		s.publishHead(head)
+		*last = head
+	}
+	return headNumber, nil
+}
+
+// pollHeads publica os novos blocos consultando o último bloco periodicamente
+func (s *SubscriptionManager) pollHeads(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	var last *types.Header
+	for {
+		pollCtx, cancel := context.WithTimeout(ctx, s.pollInterval)
+		head, err := s.pool.BlockNumber(pollCtx)
+		if err == nil && (last == nil || head > last.Number.Uint64()) {
+			_, err = s.publishMissedHeads(pollCtx, &last)
+		}
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			fmt.Printf("Erro ao consultar novos blocos: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SubscribeLogs assina os logs que atendem query a partir do bloco atual e os entrega no canal retornado,
+// na ordem em que chegam, até ctx ser cancelado (o canal é então fechado). Se a assinatura cair, os logs dos
+// blocos seguintes ao último log entregue são buscados por consulta antes de o fluxo continuar.
+// Logs removidos por reorganização são entregues com Removed = true.
+func (s *SubscriptionManager) SubscribeLogs(ctx context.Context, query ethereum.FilterQuery) (<-chan types.Log, error) {
+	if !s.pool.SupportsSubscriptions() {
+		return nil, ErrSubscriptionsUnsupported
+	}
+
+	out := make(chan types.Log, 64)
+	go func() {
+		defer close(out)
+		var lastBlock uint64
+		s.keepAlive(ctx, "logs", func() (bool, error) {
+			return s.streamLogs(ctx, query, out, &lastBlock)
+		})
+	}()
+	return out, nil
+}
+
+// streamLogs abre a assinatura de logs, entrega os logs perdidos desde lastBlock e repassa os novos logs
+func (s *SubscriptionManager) streamLogs(ctx context.Context, query ethereum.FilterQuery, out chan<- types.Log, lastBlock *uint64) (bool, error) {
+	ch := make(chan types.Log, 64)
+	sub, err := s.pool.SubscribeFilterLogs(ctx, query, ch)
+	if err != nil {
+		return false, err
+	}
+	defer sub.Unsubscribe()
+
+	head, err := s.pool.BlockNumber(ctx)
+	if err != nil {
+		return true, fmt.Errorf("erro ao obter último bloco: %w", err)
+	}
+
+	// logs até covered já foram entregues pela busca da lacuna
+	var covered uint64
+	if *lastBlock > 0 && head > *lastBlock {
+		missed := query
+		missed.FromBlock = new(big.Int).SetUint64(*lastBlock + 1)
+		missed.ToBlock = new(big.Int).SetUint64(head)
+		logs, err := s.pool.FilterLogs(ctx, missed)
+		if err != nil {
+			return true, fmt.Errorf("erro ao buscar logs perdidos entre os blocos %d e %d: %w", *lastBlock+1, head, err)
+		}
+		for _, log := range logs {
+			if !sendLog(ctx, out, log) {
+				return true, ctx.Err()
+			}
+		}
+		covered = head
+	}
+	if head > *lastBlock {
+		*lastBlock = head
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case err := <-sub.Err():
+			if err == nil {
+				err = errors.New("assinatura encerrada pelo nó")
+			}
+			return true, err
+		case log := <-ch:
+			if !log.Removed && log.BlockNumber <= covered {
+				continue
+			}
+			if !sendLog(ctx, out, log) {
+				return true, ctx.Err()
+			}
+			if log.BlockNumber > *lastBlock {
+				*lastBlock = log.BlockNumber
+			}
+		}
+	}
+}
+
+func sendLog(ctx context.Context, out chan<- types.Log, log types.Log) bool {
+	select {
+	case out <- log:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,200 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/vmm2136/besu_challenge/go-app/internal/pkg/ethutils"
+)
+
+// Estratégias de escolha da conta que assina cada escrita
+const (
+	TransactorStrategyRoundRobin   = "round-robin"   // alterna entre as contas
+	TransactorStrategyLeastPending = "least-pending" // conta com menos transações ainda não mineradas
+)
+
+// ErrNoFundedTransactor indica que nenhuma conta do pool tem saldo para pagar a transação
+var ErrNoFundedTransactor = errors.New("nenhuma conta transatora com saldo suficiente")
+
+// UpfrontCost calcula o custo máximo, em wei, da transação enviada pela conta from (gas limit × preço máximo + value).
+// Um erro (como um revert na estimativa de gás) interrompe a escolha da conta.
+type UpfrontCost func(from common.Address) (*big.Int, error)
+
+// Transactors escolhe a conta que assina uma escrita; o TransactorPool é a implementação usada pela aplicação
+type Transactors interface {
+	Select(ctx context.Context, cost UpfrontCost) (ethutils.Signer, error)
+}
+
+// TransactorPoolConfig configura a escolha das contas transatoras
+type TransactorPoolConfig struct {
+	Strategy   string
+	MinBalance *big.Int      // saldo mínimo, em wei, exigido além de cobrir o custo da transação (padrão 0)
+	BalanceTTL time.Duration // por quanto tempo o saldo consultado é reaproveitado
+}
+
+// TransactorInfo descreve uma conta do pool
+type TransactorInfo struct {
+	Address common.Address
+	Balance *big.Int // nil se o saldo não pôde ser consultado
+	Pending int      // transações enviadas e ainda não mineradas
+	Funded  bool     // saldo acima do mínimo configurado
+}
+
+// cachedBalance é o último saldo consultado de uma conta
+type cachedBalance struct {
+	value     *big.Int
+	funded    bool
+	checkedAt time.Time
+}
+
+// TransactorPool distribui as escritas entre várias contas, cada uma com a sua sequência de nonces no NonceManager,
+// para que transações de contas diferentes não esperem umas pelas outras. Contas sem saldo para pagar a transação
+// (ou abaixo do mínimo configurado) são puladas.
+type TransactorPool struct {
+	client    *RPCPool
+	tracker   *TxTracker
+	signers   []ethutils.Signer
+	byAddress map[common.Address]ethutils.Signer
+	config    TransactorPoolConfig
+	next      atomic.Uint64
+
+	mu       sync.Mutex
+	balances map[common.Address]cachedBalance
+}
+
+// NewTransactorPool cria o pool de contas transatoras do Registry
+func (r *Registry) NewTransactorPool(signers []ethutils.Signer, config TransactorPoolConfig) (*TransactorPool, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("pool de transatores precisa de ao menos uma conta")
+	}
+	if config.Strategy != TransactorStrategyRoundRobin && config.Strategy != TransactorStrategyLeastPending {
+		return nil, fmt.Errorf("estratégia de escolha de transator inválida: %s", config.Strategy)
+	}
+	if config.MinBalance == nil {
+		config.MinBalance = big.NewInt(0)
+	}
+
+	byAddress := make(map[common.Address]ethutils.Signer, len(signers))
+	var unique []ethutils.Signer
+	for _, signer := range signers {
+		if _, ok := byAddress[signer.Address()]; ok {
+			continue
+		}
+		byAddress[signer.Address()] = signer
+		unique = append(unique, signer)
+	}
+
+	return &TransactorPool{
+		client:    r.client,
+		tracker:   r.monitor.tracker,
+		signers:   unique,
+		byAddress: byAddress,
+		config:    config,
+		balances:  make(map[common.Address]cachedBalance),
+	}, nil
+}
+
+// Select escolhe a conta que assina a próxima escrita, pela estratégia configurada. O saldo de cada conta é comparado
+// com o custo da transação calculado por cost para ela (e com o mínimo configurado); contas sem saldo são puladas.
+func (p *TransactorPool) Select(ctx context.Context, cost UpfrontCost) (ethutils.Signer, error) {
+	var lastErr error
+	for _, signer := range p.ordered() {
+		address := signer.Address()
+		balance, err := p.balance(ctx, address)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		required, err := cost(address)
+		if err != nil {
+			return nil, err
+		}
+		if required.Cmp(p.config.MinBalance) < 0 {
+			required = p.config.MinBalance
+		}
+		if balance.value.Cmp(required) >= 0 {
+			return signer, nil
+		}
+		lastErr = fmt.Errorf("saldo de %s é %s wei, a transação exige %s wei", address.Hex(), balance.value, required)
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoFundedTransactor, lastErr)
+	}
+	return nil, ErrNoFundedTransactor
+}
+
+// SignerFor retorna o assinador de uma conta do pool, usado para substituir transações enviadas por ela
+func (p *TransactorPool) SignerFor(address common.Address) (ethutils.Signer, bool) {
+	signer, ok := p.byAddress[address]
+	return signer, ok
+}
+
+// Accounts descreve as contas do pool, na ordem configurada
+func (p *TransactorPool) Accounts(ctx context.Context) []TransactorInfo {
+	accounts := make([]TransactorInfo, 0, len(p.signers))
+	for _, signer := range p.signers {
+		address := signer.Address()
+		info := TransactorInfo{Address: address, Pending: p.tracker.PendingCount(address)}
+		if balance, err := p.balance(ctx, address); err == nil {
+			info.Balance = balance.value
+			info.Funded = balance.funded
+		}
+		accounts = append(accounts, info)
+	}
+	return accounts
+}
+
+// ordered retorna as contas na ordem em que devem ser tentadas. O rodízio também desempata o least-pending,
+// para que contas igualmente livres se alternem.
+func (p *TransactorPool) ordered() []ethutils.Signer {
+	start := int(p.next.Add(1)-1) % len(p.signers)
+	ordered := make([]ethutils.Signer, 0, len(p.signers))
+	ordered = append(ordered, p.signers[start:]...)
+	ordered = append(ordered, p.signers[:start]...)
+
+	if p.config.Strategy == TransactorStrategyLeastPending {
+		pending := make(map[common.Address]int, len(ordered))
+		for _, signer := range ordered {
+			pending[signer.Address()] = p.tracker.PendingCount(signer.Address())
+		}
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return pending[ordered[i].Address()] < pending[ordered[j].Address()]
+		})
+	}
+	return ordered
+}
+
+// balance retorna o saldo da conta, consultando o nó se o último valor tiver mais de BalanceTTL
+func (p *TransactorPool) balance(ctx context.Context, address common.Address) (cachedBalance, error) {
+	p.mu.Lock()
+	cached, ok := p.balances[address]
+	p.mu.Unlock()
+	if ok && time.Since(cached.checkedAt) < p.config.BalanceTTL {
+		return cached, nil
+	}
+
+	value, err := p.client.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return cachedBalance{}, fmt.Errorf("erro ao consultar saldo de %s: %w", address.Hex(), err)
+	}
+	current := cachedBalance{value: value, funded: value.Cmp(p.config.MinBalance) >= 0, checkedAt: time.Now()}
+
+	p.mu.Lock()
+	p.balances[address] = current
+	p.mu.Unlock()
+
+	switch {
+	case !current.funded && (!ok || cached.funded):
+		fmt.Printf("Conta transatora %s com saldo %s wei, abaixo do mínimo de %s; ela não será usada\n", address.Hex(), value, p.config.MinBalance)
+	case current.funded && ok && !cached.funded:
+		fmt.Printf("Conta transatora %s voltou a ter saldo suficiente (%s wei)\n", address.Hex(), value)
+	}
+	return current, nil
+}
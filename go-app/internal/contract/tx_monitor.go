@@ -0,0 +1,204 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxMonitor acompanha, junto ao nó, o ciclo de vida das transações enviadas pela API.
+// É compartilhado por todos os contratos do Registry, assim como o TxTracker e o NonceManager que ele usa.
+type TxMonitor struct {
+	client        *RPCPool
+	tracker       *TxTracker
+	nonces        *NonceManager
+	subscriptions *SubscriptionManager
+}
+
+// NewTxMonitor cria um TxMonitor. As transações acompanhadas são consultadas a cada novo bloco recebido
+// pelo gerenciador de assinaturas, além da consulta periódica do TxTracker.
+func NewTxMonitor(client *RPCPool, tracker *TxTracker, nonces *NonceManager, subscriptions *SubscriptionManager) (*TxMonitor, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client Besu não pode ser nulo")
+	}
+	if tracker == nil {
+		return nil, fmt.Errorf("rastreador de transações não pode ser nulo")
+	}
+	if nonces == nil {
+		return nil, fmt.Errorf("gerenciador de nonces não pode ser nulo")
+	}
+	if subscriptions == nil {
+		return nil, fmt.Errorf("gerenciador de assinaturas não pode ser nulo")
+	}
+	return &TxMonitor{client: client, tracker: tracker, nonces: nonces, subscriptions: subscriptions}, nil
+}
+
+// NewHeads registra um assinante dos novos blocos da rede; a função retornada cancela o registro
+func (m *TxMonitor) NewHeads() (<-chan *types.Header, func()) {
+	return m.subscriptions.NewHeads()
+}
+
+// TransactionStatus consulta o nó e retorna o estado atual de uma transação
+func (m *TxMonitor) TransactionStatus(ctx context.Context, hash common.Hash) (*TxStatus, error) {
+	status, isTracked := m.tracker.Get(hash)
+	if isTracked && status.IsFinal() {
+		return &status, nil
+	}
+	status.Hash = hash
+
+	receipt, err := m.client.TransactionReceipt(ctx, hash)
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return nil, fmt.Errorf("erro ao buscar recibo da transação %s: %w", hash.Hex(), err)
+	}
+
+	if receipt != nil {
+		head, err := m.client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao obter número do último bloco: %w", err)
+		}
+
+		status.BlockNumber = receipt.BlockNumber.Uint64()
+		status.BlockHash = receipt.BlockHash
+		status.GasUsed = receipt.GasUsed
+		status.Confirmations = 0
+		if head >= status.BlockNumber {
+			status.Confirmations = head - status.BlockNumber + 1
+		}
+
+		switch {
+		case receipt.Status == types.ReceiptStatusFailed:
+			status.State = TxStateReverted
+		case status.Confirmations >= m.tracker.confirmations:
+			status.State = TxStateConfirmed
+		default:
+			status.State = TxStateMined
+		}
+	} else {
+		tx, isPending, err := m.client.TransactionByHash(ctx, hash)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("erro ao buscar transação %s: %w", hash.Hex(), err)
+		}
+
+		switch {
+		case tx != nil && isPending:
+			status.State = TxStatePending
+		case !isTracked:
+			return nil, ErrTxNotFound
+		case status.ReplacedBy != (common.Hash{}):
+			status.State = TxStateReplaced
+		case status.State == TxStateDropped || m.tracker.isDropped(hash):
+			status.State = TxStateDropped
+		default:
+			status.State = TxStateSubmitted
+		}
+	}
+
+	if isTracked {
+		m.tracker.update(ctx, status)
+		status, _ = m.tracker.Get(hash)
+	}
+	return &status, nil
+}
+
+// WaitForTransaction aguarda até a transação ser minerada, ser descartada ou atingir um estado final.
+// Se o contexto expirar antes disso, retorna o último estado conhecido junto com o erro do contexto.
+func (m *TxMonitor) WaitForTransaction(ctx context.Context, hash common.Hash) (*TxStatus, error) {
+	return m.waitForState(ctx, hash, func(status *TxStatus) bool {
+		return status.IsFinal() || status.State == TxStateMined || status.State == TxStateDropped
+	})
+}
+
+// WaitForConfirmation aguarda até a transação atingir um estado final (confirmada ou revertida) ou ser descartada
+func (m *TxMonitor) WaitForConfirmation(ctx context.Context, hash common.Hash) (*TxStatus, error) {
+	return m.waitForState(ctx, hash, func(status *TxStatus) bool {
+		return status.IsFinal() || status.State == TxStateDropped
+	})
+}
+
+func (m *TxMonitor) waitForState(ctx context.Context, hash common.Hash, reached func(*TxStatus) bool) (*TxStatus, error) {
+	ticker := time.NewTicker(m.tracker.pollInterval)
+	defer ticker.Stop()
+	heads, unsubscribe := m.subscriptions.NewHeads()
+	defer unsubscribe()
+
+	var last *TxStatus
+	for {
+		status, err := m.TransactionStatus(ctx, hash)
+		if err != nil && ctx.Err() == nil {
+			return nil, err
+		}
+		if status != nil {
+			last = status
+			if reached(status) {
+				return status, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-ticker.C:
+		case <-heads:
+		}
+	}
+}
+
+// watchTransaction acompanha em segundo plano uma transação enviada até que ela atinja um estado final
+func (m *TxMonitor) watchTransaction(hash common.Hash) {
+	timeout := 2 * m.tracker.dropTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(m.tracker.pollInterval)
+	defer ticker.Stop()
+	heads, unsubscribe := m.subscriptions.NewHeads()
+	defer unsubscribe()
+
+	for {
+		status, err := m.TransactionStatus(ctx, hash)
+		if err != nil && ctx.Err() == nil {
+			fmt.Printf("Erro ao acompanhar transação %s: %v\n", hash.Hex(), err)
+		}
+		// a substituta é acompanhada por conta própria; uma descartada que for minerada depois é detectada
+		// na próxima consulta de TransactionStatus, que volta a buscar o recibo
+		if status != nil && (status.IsFinal() || status.State == TxStateReplaced || status.State == TxStateDropped) {
+			if status.State == TxStateDropped {
+				m.resetNoncesAfterDrop(ctx, status.From, hash)
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-heads:
+		}
+	}
+}
+
+// resetNoncesAfterDrop fecha a lacuna que uma transação descartada deixa na sequência de nonces da conta.
+// A sequência só volta ao nonce pendente do nó quando a conta não tem outras transações em andamento, para não
+// distribuir de novo nonces ainda em uso; as transações seguintes, presas atrás da lacuna, acabam descartadas
+// também e a última delas faz a volta.
+func (m *TxMonitor) resetNoncesAfterDrop(ctx context.Context, from common.Address, hash common.Hash) {
+	if m.tracker.PendingCount(from) > 0 {
+		return
+	}
+	reset, err := m.nonces.Reset(ctx, from)
+	if err != nil {
+		fmt.Printf("Erro ao ressincronizar nonce após descarte da transação %s: %v\n", hash.Hex(), err)
+		return
+	}
+	if reset {
+		fmt.Printf("Sequência de nonces da conta %s ressincronizada após descarte da transação %s\n", from.Hex(), hash.Hex())
+	}
+}
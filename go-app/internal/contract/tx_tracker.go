@@ -0,0 +1,263 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxState representa uma etapa do ciclo de vida de uma transação
+type TxState string
+
+const (
+	TxStateSubmitted TxState = "submitted" // enviada pela API, ainda não vista no mempool do nó
+	TxStatePending   TxState = "pending"   // presente no mempool do nó
+	TxStateMined     TxState = "mined"     // incluída em um bloco, aguardando confirmações
+	TxStateConfirmed TxState = "confirmed" // incluída e com o número mínimo de confirmações
+	TxStateReverted  TxState = "reverted"  // incluída em um bloco, mas a execução falhou
+	TxStateDropped   TxState = "dropped"   // sumiu do mempool sem ser minerada
+	TxStateReplaced  TxState = "replaced"  // substituída por outra transação com o mesmo nonce (speedup/cancel)
+	TxStateFailed    TxState = "failed"    // assinada e registrada, mas recusada pelo nó no envio
+)
+
+// ErrTxNotFound indica que a transação não é conhecida pela API nem pelo nó
+var ErrTxNotFound = errors.New("transação não encontrada")
+
+// ErrSendOutcomeUnknown indica que o envio falhou sem resposta do nó (tempo esgotado, conexão interrompida):
+// a transação pode ter sido aceita e continua sendo acompanhada pelo hash informado no erro
+var ErrSendOutcomeUnknown = errors.New("resultado do envio desconhecido")
+
+// TxStatus descreve o estado atual de uma transação
+type TxStatus struct {
+	Hash          common.Hash
+	State         TxState
+	From          common.Address
+	Nonce         uint64
+	BlockNumber   uint64
+	BlockHash     common.Hash
+	GasUsed       uint64
+	Confirmations uint64
+	ReplacedBy    common.Hash // transação que substituiu esta, se houver
+	Replaces      common.Hash // transação substituída por esta, se houver
+	SubmittedAt   time.Time
+	UpdatedAt     time.Time
+}
+
+// IsFinal indica se a transação não vai mais mudar de estado.
+// Transações substituídas e descartadas não são finais: se forem mineradas depois (a substituída antes da
+// substituta, a descartada por um nó que ainda a tinha no mempool), passam a mined/confirmed.
+func (s *TxStatus) IsFinal() bool {
+	return s.State == TxStateConfirmed || s.State == TxStateReverted || s.State == TxStateFailed
+}
+
+// trackedTx é o registro interno de uma transação enviada pela API
+type trackedTx struct {
+	tx            *types.Transaction
+	status        TxStatus
+	lastBroadcast time.Time // último envio ao nó, base para detectar o descarte
+}
+
+// TxTracker guarda em memória as transações enviadas pela API e o seu último estado conhecido.
+// Cada transação é gravada no TxJournal antes do envio e cada mudança de estado é persistida nele.
+type TxTracker struct {
+	mu            sync.RWMutex
+	txs           map[common.Hash]*trackedTx
+	journal       TxJournal
+	confirmations uint64
+	dropTimeout   time.Duration
+	pollInterval  time.Duration
+}
+
+// NewTxTracker cria um TxTracker.
+// confirmations é o número de blocos (incluindo o da transação) para considerá-la confirmada e
+// dropTimeout é o tempo sem aparecer no nó após o qual uma transação é dada como descartada.
+func NewTxTracker(confirmations uint64, dropTimeout, pollInterval time.Duration, journal TxJournal) (*TxTracker, error) {
+	if journal == nil {
+		return nil, fmt.Errorf("journal de transações não pode ser nulo")
+	}
+	if confirmations == 0 {
+		confirmations = 1
+	}
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+	return &TxTracker{
+		txs:           make(map[common.Hash]*trackedTx),
+		journal:       journal,
+		confirmations: confirmations,
+		dropTimeout:   dropTimeout,
+		pollInterval:  pollInterval,
+	}, nil
+}
+
+// Prepare grava no journal uma transação assinada, antes do envio ao nó.
+// Se o envio falhar, a transação deve ser marcada com fail.
+func (t *TxTracker) Prepare(ctx context.Context, tx *types.Transaction, from common.Address, method string) error {
+	record, err := newTxRecord(ctx, tx, from, method)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar transação %s: %w", tx.Hash().Hex(), err)
+	}
+	if err := t.journal.SaveTransaction(ctx, record); err != nil {
+		return fmt.Errorf("erro ao registrar transação antes do envio: %w", err)
+	}
+	return nil
+}
+
+// Track passa a acompanhar em memória uma transação recém enviada
+func (t *TxTracker) Track(tx *types.Transaction, from common.Address) TxStatus {
+	now := time.Now()
+	status := TxStatus{
+		Hash:        tx.Hash(),
+		State:       TxStateSubmitted,
+		From:        from,
+		Nonce:       tx.Nonce(),
+		SubmittedAt: now,
+		UpdatedAt:   now,
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.txs[status.Hash] = &trackedTx{tx: tx, status: status, lastBroadcast: now}
+	return status
+}
+
+// restore volta a acompanhar em memória uma transação lida do journal
+func (t *TxTracker) restore(tx *types.Transaction, record TxRecord) {
+	status := TxStatus{
+		Hash:        record.Hash,
+		State:       record.State,
+		From:        record.From,
+		Nonce:       record.Nonce,
+		BlockNumber: record.BlockNumber,
+		BlockHash:   record.BlockHash,
+		ReplacedBy:  record.ReplacedBy,
+		Replaces:    record.Replaces,
+		SubmittedAt: record.SubmittedAt,
+		UpdatedAt:   time.Now(),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.txs[status.Hash] = &trackedTx{tx: tx, status: status, lastBroadcast: record.SubmittedAt}
+}
+
+// rebroadcasted registra um novo envio da transação ao nó, reiniciando a contagem para o descarte
+func (t *TxTracker) rebroadcasted(hash common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if tracked, ok := t.txs[hash]; ok {
+		tracked.lastBroadcast = time.Now()
+	}
+}
+
+// Get retorna o último estado conhecido de uma transação registrada
+func (t *TxTracker) Get(hash common.Hash) (TxStatus, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	tracked, ok := t.txs[hash]
+	if !ok {
+		return TxStatus{}, false
+	}
+	return tracked.status, true
+}
+
+// PendingCount retorna quantas transações da conta foram enviadas e ainda não foram mineradas
+func (t *TxTracker) PendingCount(from common.Address) int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	count := 0
+	for _, tracked := range t.txs {
+		state := tracked.status.State
+		if tracked.status.From == from && (state == TxStateSubmitted || state == TxStatePending) {
+			count++
+		}
+	}
+	return count
+}
+
+// Transaction retorna a transação assinada registrada para um hash
+func (t *TxTracker) Transaction(hash common.Hash) (*types.Transaction, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	tracked, ok := t.txs[hash]
+	if !ok {
+		return nil, false
+	}
+	return tracked.tx, true
+}
+
+// update grava um novo estado para uma transação registrada; estados finais não são sobrescritos.
+// Mudanças de estado ou de bloco são persistidas no journal.
+func (t *TxTracker) update(ctx context.Context, status TxStatus) {
+	t.mu.Lock()
+	tracked, ok := t.txs[status.Hash]
+	if !ok || tracked.status.IsFinal() {
+		t.mu.Unlock()
+		return
+	}
+	changed := tracked.status.State != status.State || tracked.status.BlockHash != status.BlockHash
+	status.From = tracked.status.From
+	status.Nonce = tracked.status.Nonce
+	status.ReplacedBy = tracked.status.ReplacedBy
+	status.Replaces = tracked.status.Replaces
+	status.SubmittedAt = tracked.status.SubmittedAt
+	status.UpdatedAt = time.Now()
+	tracked.status = status
+	t.mu.Unlock()
+
+	if changed {
+		t.persist(ctx, status)
+	}
+}
+
+// fail marca como recusada pelo nó uma transação registrada com Prepare
+func (t *TxTracker) fail(ctx context.Context, hash common.Hash) {
+	t.persist(ctx, TxStatus{Hash: hash, State: TxStateFailed})
+}
+
+// markReplaced registra que a transação original foi substituída pela transação replacement
+func (t *TxTracker) markReplaced(ctx context.Context, original, replacement common.Hash) {
+	t.mu.Lock()
+	var originalStatus, replacementStatus *TxStatus
+	if tracked, ok := t.txs[original]; ok && !tracked.status.IsFinal() {
+		tracked.status.State = TxStateReplaced
+		tracked.status.ReplacedBy = replacement
+		tracked.status.UpdatedAt = time.Now()
+		status := tracked.status
+		originalStatus = &status
+	}
+	if tracked, ok := t.txs[replacement]; ok {
+		tracked.status.Replaces = original
+		status := tracked.status
+		replacementStatus = &status
+	}
+	t.mu.Unlock()
+
+	if originalStatus != nil {
+		t.persist(ctx, *originalStatus)
+	}
+	if replacementStatus != nil {
+		t.persist(ctx, *replacementStatus)
+	}
+}
+
+// persist grava o estado no journal. Uma falha não interrompe o acompanhamento: o estado em memória continua
+// válido e o próximo update ou a recuperação na inicialização reconciliam o registro.
+func (t *TxTracker) persist(ctx context.Context, status TxStatus) {
+	if err := t.journal.UpdateTransactionState(ctx, status); err != nil {
+		fmt.Printf("Erro ao persistir estado '%s' da transação %s: %v\n", status.State, status.Hash.Hex(), err)
+	}
+}
+
+// isDropped indica se uma transação registrada já passou do tempo limite, desde o último envio, sem aparecer no nó
+func (t *TxTracker) isDropped(hash common.Hash) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	tracked, ok := t.txs[hash]
+	return ok && t.dropTimeout > 0 && time.Since(tracked.lastBroadcast) > t.dropTimeout
+}
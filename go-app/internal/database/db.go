@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"math/big"
+	"time"
 
 	_ "github.com/lib/pq"
 )
@@ -14,6 +15,32 @@ type DBClient interface {
 	GetContractValue(ctx context.Context, key string) (*big.Int, error)
 	SaveContractValue(ctx context.Context, key string, value *big.Int) error
 	ValidateContractValue(ctx context.Context, key string, expectedValue *big.Int) (bool, error)
+	GetSyncedValue(ctx context.Context, key string) (*SyncedValue, error)
+	SaveSyncedValue(ctx context.Context, key string, synced SyncedValue) error
+	GetIndexerCursor(ctx context.Context, name string) (uint64, bool, error)
+	SaveValueChanges(ctx context.Context, cursorName string, toBlock uint64, records []ValueChangeRecord) error
+	InsertValueChanges(ctx context.Context, records []ValueChangeRecord) error
+	AppendValueHistory(ctx context.Context, record ValueChangeRecord) error
+	ListValueHistory(ctx context.Context, filter HistoryFilter) ([]HistoryEntry, int, error)
+	ListHistoryCheckpoints(ctx context.Context, contractAddress string, maxBlock uint64, limit int) ([]BlockCheckpoint, error)
+	RollbackToBlock(ctx context.Context, contractAddress, cursorName string, forkBlock uint64) (int64, error)
+	InsertTransaction(ctx context.Context, record TransactionRecord) error
+	UpdateTransactionStatus(ctx context.Context, update TransactionStatusUpdate) error
+	GetTransaction(ctx context.Context, txHash string) (*TransactionRecord, error)
+	ListTransactionsByStatus(ctx context.Context, statuses []string) ([]TransactionRecord, error)
+	GetTransactionByIdempotencyKey(ctx context.Context, key string) (*TransactionRecord, error)
+	ReserveIdempotencyKey(ctx context.Context, key, requestHash string, ttl time.Duration) (bool, *IdempotencyRecord, error)
+	CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, responseBody []byte, txHash string) error
+	ClaimStaleIdempotencyKey(ctx context.Context, key string, staleAfter time.Duration) (bool, error)
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+}
+
+// SyncedValue é o valor do contrato junto com o bloco em que foi lido.
+// BlockHash fica vazio para valores salvos antes do registro do bloco.
+type SyncedValue struct {
+	Value       *big.Int
+	BlockNumber uint64
+	BlockHash   string
 }
 
 // SQLDBClient é a implementação para bancos de dados SQL
@@ -85,3 +112,48 @@ func (c *SQLDBClient) ValidateContractValue(ctx context.Context, key string, exp
 	}
 	return false, nil
 }
+
+// GetSyncedValue obtém o valor salvo e o bloco em que foi lido. Retorna nil se a chave não existe.
+func (c *SQLDBClient) GetSyncedValue(ctx context.Context, key string) (*SyncedValue, error) {
+	var valueStr string
+	var blockNumber sql.NullInt64
+	var blockHash sql.NullString
+
+	query := `SELECT contract_value, block_number, block_hash FROM contract_values WHERE contract_key = $1`
+	err := c.db.QueryRowContext(ctx, query, key).Scan(&valueStr, &blockNumber, &blockHash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar valor sincronizado para chave '%s' no DB: %w", key, err)
+	}
+
+	value, ok := new(big.Int).SetString(valueStr, 10)
+	if !ok {
+		return nil, fmt.Errorf("erro ao converter valor '%s' do DB para big.Int para chave '%s'", valueStr, key)
+	}
+	return &SyncedValue{
+		Value:       value,
+		BlockNumber: uint64(blockNumber.Int64),
+		BlockHash:   blockHash.String,
+	}, nil
+}
+
+// SaveSyncedValue faz um UPSERT do valor junto com o número e o hash do bloco em que foi lido
+func (c *SQLDBClient) SaveSyncedValue(ctx context.Context, key string, synced SyncedValue) error {
+	upsertSQL := `
+	INSERT INTO contract_values (contract_key, contract_value, block_number, block_hash)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (contract_key) DO UPDATE
+	SET contract_value = EXCLUDED.contract_value,
+	    block_number = EXCLUDED.block_number,
+	    block_hash = EXCLUDED.block_hash,
+	    created_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := c.db.ExecContext(ctx, upsertSQL, key, synced.Value.String(), int64(synced.BlockNumber), synced.BlockHash)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar valor '%s' do bloco %d para chave '%s' no DB: %w", synced.Value.String(), synced.BlockNumber, key, err)
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Origens possíveis de um registro do histórico
+const (
+	HistorySourceEvent = "event" // log ValueChanged lido pelo indexador
+	HistorySourceSync  = "sync"  // alteração detectada pela sincronização
+	HistorySourceWrite = "write" // escrita feita pela API e confirmada na rede
+)
+
+// ValueChangeRecord representa uma alteração de valor do contrato registrada no histórico.
+// Campos vazios (ou nil) são gravados como NULL quando a origem não os conhece.
+type ValueChangeRecord struct {
+	ContractAddress string
+	Source          string
+	BlockNumber     uint64
+	BlockHash       string
+	TxHash          string
+	LogIndex        *uint
+	Setter          string
+	OldValue        *big.Int
+	NewValue        *big.Int
+}
+
+// HistoryEntry é um registro lido do histórico
+type HistoryEntry struct {
+	ID int64
+	ValueChangeRecord
+	CreatedAt time.Time
+}
+
+// HistoryFilter define os filtros e a paginação da consulta ao histórico. Campos nil não filtram.
+type HistoryFilter struct {
+	ContractAddress string
+	Setter          string
+	FromTime        *time.Time
+	ToTime          *time.Time
+	FromBlock       *uint64
+	ToBlock         *uint64
+	Limit           int
+	Offset          int
+}
+
+// BlockCheckpoint é um bloco (número e hash) já registrado no histórico
+type BlockCheckpoint struct {
+	BlockNumber uint64
+	BlockHash   string
+}
+
+const insertHistorySQL = `
+	INSERT INTO contract_value_history
+	    (contract_address, source, block_number, block_hash, tx_hash, log_index, setter, old_value, new_value)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT (tx_hash, log_index) DO NOTHING
+	`
+
+// insertSyncHistorySQL grava uma alteração detectada pela sincronização apenas se ela ainda não está no histórico,
+// isto é, se o último registro com transação (evento ou escrita) até o bloco lido não tem o mesmo valor
+const insertSyncHistorySQL = `
+	INSERT INTO contract_value_history
+	    (contract_address, source, block_number, block_hash, tx_hash, log_index, setter, old_value, new_value)
+	SELECT $1::text, $2::text, $3::bigint, $4::text, $5::text, $6::integer, $7::text, $8::text, $9::text
+	WHERE NOT EXISTS (
+	    SELECT 1 FROM (
+	        SELECT new_value FROM contract_value_history
+	        WHERE contract_address = $1 AND tx_hash IS NOT NULL AND block_number <= $3
+	        ORDER BY block_number DESC, log_index DESC NULLS LAST
+	        LIMIT 1
+	    ) latest
+	    WHERE latest.new_value = $9
+	)
+	`
+
+// supersededSyncCondition identifica, na listagem, os registros da sincronização explicados por uma transação gravada
+// depois deles: o último registro com transação até o bloco lido tem o mesmo valor. Os registros continuam na tabela.
+const supersededSyncCondition = `NOT (
+	    history.source = 'sync' AND history.tx_hash IS NULL AND COALESCE((
+	        SELECT latest.new_value FROM contract_value_history latest
+	        WHERE latest.contract_address = history.contract_address AND latest.tx_hash IS NOT NULL
+	          AND latest.block_number <= history.block_number
+	        ORDER BY latest.block_number DESC, latest.log_index DESC NULLS LAST
+	        LIMIT 1
+	    ) = history.new_value, false)
+	)`
+
+// execer é a parte comum de *sql.DB e *sql.Tx usada para gravar o histórico
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// GetIndexerCursor obtém o último bloco processado por um indexador. Retorna false se o cursor ainda não existe.
+func (c *SQLDBClient) GetIndexerCursor(ctx context.Context, name string) (uint64, bool, error) {
+	var blockNumber int64
+
+	query := `SELECT block_number FROM indexer_cursors WHERE cursor_name = $1`
+	err := c.db.QueryRowContext(ctx, query, name).Scan(&blockNumber)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("erro ao buscar cursor '%s' no DB: %w", name, err)
+	}
+	return uint64(blockNumber), true, nil
+}
+
+// SaveValueChanges grava as alterações no histórico e avança o cursor do indexador até toBlock na mesma transação.
+// Registros já existentes (mesmo tx_hash e log_index) são ignorados, o que torna a operação idempotente.
+func (c *SQLDBClient) SaveValueChanges(ctx context.Context, cursorName string, toBlock uint64, records []ValueChangeRecord) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação no DB: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, record := range records {
+		if err := insertHistory(ctx, tx, record); err != nil {
+			return err
+		}
+	}
+
+	upsertCursorSQL := `
+	INSERT INTO indexer_cursors (cursor_name, block_number)
+	VALUES ($1, $2)
+	ON CONFLICT (cursor_name) DO UPDATE
+	SET block_number = EXCLUDED.block_number,
+	    updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := tx.ExecContext(ctx, upsertCursorSQL, cursorName, int64(toBlock)); err != nil {
+		return fmt.Errorf("erro ao atualizar cursor '%s' no DB: %w", cursorName, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação no DB: %w", err)
+	}
+	return nil
+}
+
+// InsertValueChanges grava as alterações no histórico em uma única transação, sem mover nenhum cursor.
+// Registros já existentes (mesmo tx_hash e log_index) são ignorados.
+func (c *SQLDBClient) InsertValueChanges(ctx context.Context, records []ValueChangeRecord) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação no DB: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, record := range records {
+		if err := insertHistory(ctx, tx, record); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação no DB: %w", err)
+	}
+	return nil
+}
+
+// AppendValueHistory acrescenta um registro ao histórico
+func (c *SQLDBClient) AppendValueHistory(ctx context.Context, record ValueChangeRecord) error {
+	return insertHistory(ctx, c.db, record)
+}
+
+// ListValueHistory lista o histórico do mais recente para o mais antigo e retorna também o total de registros do filtro.
+// Registros da sincronização já explicados por um evento ou escrita ficam de fora.
+func (c *SQLDBClient) ListValueHistory(ctx context.Context, filter HistoryFilter) ([]HistoryEntry, int, error) {
+	conditions := []string{supersededSyncCondition}
+	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ContractAddress != "" {
+		addCondition("contract_address = $%d", filter.ContractAddress)
+	}
+	if filter.Setter != "" {
+		addCondition("setter = $%d", filter.Setter)
+	}
+	if filter.FromTime != nil {
+		addCondition("created_at >= $%d", *filter.FromTime)
+	}
+	if filter.ToTime != nil {
+		addCondition("created_at <= $%d", *filter.ToTime)
+	}
+	if filter.FromBlock != nil {
+		addCondition("block_number >= $%d", int64(*filter.FromBlock))
+	}
+	if filter.ToBlock != nil {
+		addCondition("block_number <= $%d", int64(*filter.ToBlock))
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	countSQL := `SELECT COUNT(*) FROM contract_value_history history ` + where
+	if err := c.db.QueryRowContext(ctx, countSQL, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar histórico no DB: %w", err)
+	}
+
+	listSQL := fmt.Sprintf(`
+	SELECT id, contract_address, source, block_number, block_hash, tx_hash, log_index, setter, old_value, new_value, created_at
+	FROM contract_value_history history
+	%s
+	ORDER BY block_number DESC, log_index DESC NULLS LAST, id DESC
+	LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+
+	rows, err := c.db.QueryContext(ctx, listSQL, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar histórico no DB: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []HistoryEntry{}
+	for rows.Next() {
+		entry, err := scanHistoryEntry(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("erro ao ler histórico do DB: %w", err)
+	}
+	return entries, total, nil
+}
+
+// ListHistoryCheckpoints lista, do mais alto para o mais baixo, os blocos com hash registrados no histórico até maxBlock
+func (c *SQLDBClient) ListHistoryCheckpoints(ctx context.Context, contractAddress string, maxBlock uint64, limit int) ([]BlockCheckpoint, error) {
+	query := `
+	SELECT DISTINCT block_number, block_hash
+	FROM contract_value_history
+	WHERE contract_address = $1 AND block_hash IS NOT NULL AND block_number <= $2
+	ORDER BY block_number DESC
+	LIMIT $3
+	`
+	rows, err := c.db.QueryContext(ctx, query, contractAddress, int64(maxBlock), limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar blocos do histórico no DB: %w", err)
+	}
+	defer rows.Close()
+
+	var checkpoints []BlockCheckpoint
+	for rows.Next() {
+		var blockNumber int64
+		var checkpoint BlockCheckpoint
+		if err := rows.Scan(&blockNumber, &checkpoint.BlockHash); err != nil {
+			return nil, fmt.Errorf("erro ao ler bloco do histórico: %w", err)
+		}
+		checkpoint.BlockNumber = uint64(blockNumber)
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+// RollbackToBlock descarta o histórico posterior ao bloco de fork e recua o cursor do indexador até ele.
+// Retorna o número de registros removidos do histórico.
+func (c *SQLDBClient) RollbackToBlock(ctx context.Context, contractAddress, cursorName string, forkBlock uint64) (int64, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação no DB: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM contract_value_history WHERE contract_address = $1 AND block_number > $2`,
+		contractAddress, int64(forkBlock))
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover histórico após o bloco %d no DB: %w", forkBlock, err)
+	}
+	removed, _ := result.RowsAffected()
+
+	_, err = tx.ExecContext(ctx, `
+	UPDATE indexer_cursors
+	SET block_number = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE cursor_name = $1 AND block_number > $2
+	`, cursorName, int64(forkBlock))
+	if err != nil {
+		return 0, fmt.Errorf("erro ao recuar cursor '%s' no DB: %w", cursorName, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("erro ao confirmar transação no DB: %w", err)
+	}
+	return removed, nil
+}
+
+func insertHistory(ctx context.Context, db execer, record ValueChangeRecord) error {
+	var logIndex, oldValue interface{}
+	if record.LogIndex != nil {
+		logIndex = int64(*record.LogIndex)
+	}
+	if record.OldValue != nil {
+		oldValue = record.OldValue.String()
+	}
+
+	// a sincronização e o indexador podem registrar a mesma alteração: o registro da sincronização (sem tx_hash)
+	// só é gravado se nenhum evento a explica; se o evento chegar depois, a listagem deixa de mostrá-lo
+	query := insertHistorySQL
+	if record.Source == HistorySourceSync {
+		query = insertSyncHistorySQL
+	}
+	_, err := db.ExecContext(ctx, query,
+		record.ContractAddress, record.Source, int64(record.BlockNumber), nullIfEmpty(record.BlockHash),
+		nullIfEmpty(record.TxHash), logIndex, nullIfEmpty(record.Setter), oldValue, record.NewValue.String())
+	if err != nil {
+		return fmt.Errorf("erro ao inserir histórico (%s, bloco %d) no DB: %w", record.Source, record.BlockNumber, err)
+	}
+	return nil
+}
+
+func scanHistoryEntry(rows *sql.Rows) (HistoryEntry, error) {
+	var entry HistoryEntry
+	var blockNumber int64
+	var blockHash, txHash, setter, oldValue sql.NullString
+	var logIndex sql.NullInt64
+	var newValue string
+
+	err := rows.Scan(&entry.ID, &entry.ContractAddress, &entry.Source, &blockNumber, &blockHash, &txHash,
+		&logIndex, &setter, &oldValue, &newValue, &entry.CreatedAt)
+	if err != nil {
+		return HistoryEntry{}, fmt.Errorf("erro ao ler registro do histórico: %w", err)
+	}
+
+	entry.BlockNumber = uint64(blockNumber)
+	entry.BlockHash = blockHash.String
+	entry.TxHash = txHash.String
+	entry.Setter = setter.String
+	if logIndex.Valid {
+		index := uint(logIndex.Int64)
+		entry.LogIndex = &index
+	}
+
+	var ok bool
+	if entry.NewValue, ok = new(big.Int).SetString(newValue, 10); !ok {
+		return HistoryEntry{}, fmt.Errorf("erro ao converter valor '%s' do histórico (id %d) para big.Int", newValue, entry.ID)
+	}
+	if oldValue.Valid {
+		if entry.OldValue, ok = new(big.Int).SetString(oldValue.String, 10); !ok {
+			return HistoryEntry{}, fmt.Errorf("erro ao converter valor '%s' do histórico (id %d) para big.Int", oldValue.String, entry.ID)
+		}
+	}
+	return entry, nil
+}
+
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
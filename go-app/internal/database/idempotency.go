@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// IdempotencyRecord é a requisição registrada para uma chave de idempotência.
+// StatusCode zero indica que a requisição original ainda está em andamento.
+type IdempotencyRecord struct {
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ResponseBody []byte
+	TxHash       string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// ReserveIdempotencyKey registra a chave para uma nova requisição, válida por ttl.
+// Se a chave já estiver registrada (e não expirada), retorna false e o registro existente.
+func (c *SQLDBClient) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, ttl time.Duration) (bool, *IdempotencyRecord, error) {
+	// chaves expiradas podem ser reutilizadas
+	if _, err := c.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= CURRENT_TIMESTAMP`); err != nil {
+		return false, nil, fmt.Errorf("erro ao remover chaves de idempotência expiradas no DB: %w", err)
+	}
+
+	insertSQL := `
+	INSERT INTO idempotency_keys (idempotency_key, request_hash, expires_at)
+	VALUES ($1, $2, CURRENT_TIMESTAMP + $3 * INTERVAL '1 second')
+	ON CONFLICT (idempotency_key) DO NOTHING
+	`
+	result, err := c.db.ExecContext(ctx, insertSQL, key, requestHash, ttl.Seconds())
+	if err != nil {
+		return false, nil, fmt.Errorf("erro ao registrar chave de idempotência '%s' no DB: %w", key, err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, nil, fmt.Errorf("erro ao registrar chave de idempotência '%s' no DB: %w", key, err)
+	}
+	if inserted > 0 {
+		return true, nil, nil
+	}
+
+	var record IdempotencyRecord
+	var statusCode sql.NullInt64
+	var txHash sql.NullString
+	query := `
+	SELECT idempotency_key, request_hash, status_code, response_body, tx_hash, created_at, expires_at
+	FROM idempotency_keys
+	WHERE idempotency_key = $1
+	`
+	err = c.db.QueryRowContext(ctx, query, key).Scan(&record.Key, &record.RequestHash, &statusCode,
+		&record.ResponseBody, &txHash, &record.CreatedAt, &record.ExpiresAt)
+	if err == sql.ErrNoRows {
+		// a chave foi liberada entre o INSERT e o SELECT; o cliente pode tentar novamente
+		return false, nil, fmt.Errorf("chave de idempotência '%s' liberada durante o registro", key)
+	}
+	if err != nil {
+		return false, nil, fmt.Errorf("erro ao buscar chave de idempotência '%s' no DB: %w", key, err)
+	}
+	record.StatusCode = int(statusCode.Int64)
+	record.TxHash = txHash.String
+	return false, &record, nil
+}
+
+// CompleteIdempotencyKey grava a resposta da requisição original da chave
+func (c *SQLDBClient) CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, responseBody []byte, txHash string) error {
+	updateSQL := `
+	UPDATE idempotency_keys
+	SET status_code = $2, response_body = $3, tx_hash = $4
+	WHERE idempotency_key = $1
+	`
+	if _, err := c.db.ExecContext(ctx, updateSQL, key, statusCode, responseBody, nullIfEmpty(txHash)); err != nil {
+		return fmt.Errorf("erro ao gravar resposta da chave de idempotência '%s' no DB: %w", key, err)
+	}
+	return nil
+}
+
+// ClaimStaleIdempotencyKey assume uma chave cuja requisição original está em andamento há mais de staleAfter,
+// deixada para trás por um processo que caiu. Retorna false se a requisição original já terminou ou ainda é recente.
+func (c *SQLDBClient) ClaimStaleIdempotencyKey(ctx context.Context, key string, staleAfter time.Duration) (bool, error) {
+	updateSQL := `
+	UPDATE idempotency_keys
+	SET created_at = CURRENT_TIMESTAMP
+	WHERE idempotency_key = $1 AND status_code IS NULL AND created_at <= CURRENT_TIMESTAMP - $2 * INTERVAL '1 second'
+	`
+	result, err := c.db.ExecContext(ctx, updateSQL, key, staleAfter.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("erro ao assumir chave de idempotência '%s' no DB: %w", key, err)
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erro ao assumir chave de idempotência '%s' no DB: %w", key, err)
+	}
+	return claimed > 0, nil
+}
+
+// DeleteIdempotencyKey libera a chave para que a requisição possa ser repetida
+func (c *SQLDBClient) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	if _, err := c.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE idempotency_key = $1`, key); err != nil {
+		return fmt.Errorf("erro ao remover chave de idempotência '%s' no DB: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("nenhuma migração embutida")
+	}
+
+	// as versões são sequenciais a partir de 1 e toda migração pode ser revertida
+	for i, migration := range migrations {
+		if want := int64(i + 1); migration.Version != want {
+			t.Errorf("migração %d (%s): esperada versão %d", migration.Version, migration.Name, want)
+		}
+		if migration.Name == "" {
+			t.Errorf("migração %d sem nome", migration.Version)
+		}
+		if strings.TrimSpace(migration.Up) == "" {
+			t.Errorf("migração %d (%s) sem script up", migration.Version, migration.Name)
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migração %d (%s) sem script down", migration.Version, migration.Name)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS indexer_cursors;
+DROP TABLE IF EXISTS contract_value_history;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS contract_value_history (
+    id BIGSERIAL PRIMARY KEY,
+    contract_address TEXT NOT NULL,
+    source TEXT NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_hash TEXT,
+    tx_hash TEXT,
+    log_index INTEGER,
+    setter TEXT,
+    old_value TEXT,
+    new_value TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_contract_value_history_block ON contract_value_history (contract_address, block_number);
+CREATE INDEX IF NOT EXISTS idx_contract_value_history_setter ON contract_value_history (contract_address, setter);
+CREATE INDEX IF NOT EXISTS idx_contract_value_history_created_at ON contract_value_history (contract_address, created_at);
+
+CREATE TABLE IF NOT EXISTS indexer_cursors (
+    cursor_name TEXT PRIMARY KEY,
+    block_number BIGINT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
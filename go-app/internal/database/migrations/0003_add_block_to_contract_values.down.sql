@@ -0,0 +1,2 @@
+ALTER TABLE contract_values DROP COLUMN IF EXISTS block_hash;
+ALTER TABLE contract_values DROP COLUMN IF EXISTS block_number;
@@ -0,0 +1,2 @@
+ALTER TABLE contract_values ADD COLUMN IF NOT EXISTS block_number BIGINT;
+ALTER TABLE contract_values ADD COLUMN IF NOT EXISTS block_hash TEXT;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS transactions (
+    tx_hash TEXT PRIMARY KEY,
+    from_address TEXT NOT NULL,
+    to_address TEXT,
+    nonce BIGINT NOT NULL,
+    tx_type SMALLINT NOT NULL,
+    raw_tx BYTEA NOT NULL,
+    gas_limit BIGINT NOT NULL,
+    gas_price TEXT,
+    gas_tip_cap TEXT,
+    gas_fee_cap TEXT,
+    value TEXT NOT NULL,
+    method TEXT,
+    requester TEXT,
+    idempotency_key TEXT,
+    status TEXT NOT NULL,
+    block_number BIGINT,
+    block_hash TEXT,
+    replaced_by TEXT,
+    replaces TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_status ON transactions (status);
+CREATE INDEX IF NOT EXISTS idx_transactions_from_nonce ON transactions (from_address, nonce);
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_transactions_idempotency_key ON transactions (idempotency_key);
@@ -0,0 +1,4 @@
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS reserved_at TIMESTAMP;
+UPDATE idempotency_keys SET reserved_at = created_at WHERE reserved_at IS NULL;
+ALTER TABLE idempotency_keys ALTER COLUMN reserved_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE idempotency_keys ALTER COLUMN reserved_at SET NOT NULL;
@@ -0,0 +1,221 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// TransactionRecord é uma transação assinada pela API, gravada antes do envio à rede.
+// Campos vazios (ou nil) são gravados como NULL.
+type TransactionRecord struct {
+	TxHash         string
+	FromAddress    string
+	ToAddress      string
+	Nonce          uint64
+	TxType         uint8
+	RawTx          []byte
+	GasLimit       uint64
+	GasPrice       *big.Int
+	GasTipCap      *big.Int
+	GasFeeCap      *big.Int
+	Value          *big.Int
+	Method         string
+	Requester      string
+	IdempotencyKey string
+	Status         string
+	BlockNumber    *uint64
+	BlockHash      string
+	ReplacedBy     string
+	Replaces       string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TransactionStatusUpdate é o novo estado de uma transação já registrada
+type TransactionStatusUpdate struct {
+	TxHash      string
+	Status      string
+	BlockNumber *uint64
+	BlockHash   string
+	ReplacedBy  string
+	Replaces    string
+}
+
+const transactionColumns = `tx_hash, from_address, to_address, nonce, tx_type, raw_tx, gas_limit, gas_price, gas_tip_cap,
+	gas_fee_cap, value, method, requester, idempotency_key, status, block_number, block_hash, replaced_by, replaces,
+	created_at, updated_at`
+
+// InsertTransaction grava uma transação assinada. Regravar o mesmo hash não altera o registro existente.
+func (c *SQLDBClient) InsertTransaction(ctx context.Context, record TransactionRecord) error {
+	insertSQL := `
+	INSERT INTO transactions
+	    (tx_hash, from_address, to_address, nonce, tx_type, raw_tx, gas_limit, gas_price, gas_tip_cap, gas_fee_cap,
+	     value, method, requester, idempotency_key, status, replaces)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	ON CONFLICT (tx_hash) DO NOTHING
+	`
+	_, err := c.db.ExecContext(ctx, insertSQL,
+		record.TxHash, record.FromAddress, nullIfEmpty(record.ToAddress), int64(record.Nonce), int16(record.TxType),
+		record.RawTx, int64(record.GasLimit), nullIfNil(record.GasPrice), nullIfNil(record.GasTipCap),
+		nullIfNil(record.GasFeeCap), record.Value.String(), nullIfEmpty(record.Method), nullIfEmpty(record.Requester),
+		nullIfEmpty(record.IdempotencyKey), record.Status, nullIfEmpty(record.Replaces))
+	if err != nil {
+		return fmt.Errorf("erro ao gravar transação %s no DB: %w", record.TxHash, err)
+	}
+	return nil
+}
+
+// UpdateTransactionStatus atualiza o estado de uma transação registrada. Campos vazios preservam o valor gravado.
+func (c *SQLDBClient) UpdateTransactionStatus(ctx context.Context, update TransactionStatusUpdate) error {
+	var blockNumber interface{}
+	if update.BlockNumber != nil {
+		blockNumber = int64(*update.BlockNumber)
+	}
+
+	updateSQL := `
+	UPDATE transactions
+	SET status = $2,
+	    block_number = COALESCE($3, block_number),
+	    block_hash = COALESCE($4, block_hash),
+	    replaced_by = COALESCE($5, replaced_by),
+	    replaces = COALESCE($6, replaces),
+	    updated_at = CURRENT_TIMESTAMP
+	WHERE tx_hash = $1
+	`
+	_, err := c.db.ExecContext(ctx, updateSQL, update.TxHash, update.Status, blockNumber,
+		nullIfEmpty(update.BlockHash), nullIfEmpty(update.ReplacedBy), nullIfEmpty(update.Replaces))
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar estado da transação %s no DB: %w", update.TxHash, err)
+	}
+	return nil
+}
+
+// GetTransaction busca uma transação registrada. Retorna nil se ela não existir.
+func (c *SQLDBClient) GetTransaction(ctx context.Context, txHash string) (*TransactionRecord, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE tx_hash = $1`
+	rows, err := c.db.QueryContext(ctx, query, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar transação %s no DB: %w", txHash, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	record, err := scanTransaction(rows)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// GetTransactionByIdempotencyKey busca a primeira transação enviada com a chave de idempotência desde a reserva
+// atual da chave e não recusada pelo nó, ignorando as substitutas (speedup/cancel). Transações de um uso anterior
+// da chave, já expirado, não contam. Retorna nil se ela não existir ou se a chave não estiver reservada.
+func (c *SQLDBClient) GetTransactionByIdempotencyKey(ctx context.Context, key string) (*TransactionRecord, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions
+	WHERE idempotency_key = $1 AND status <> 'failed' AND replaces IS NULL
+	  AND created_at >= (SELECT reserved_at FROM idempotency_keys WHERE idempotency_key = $1)
+	ORDER BY created_at LIMIT 1`
+	rows, err := c.db.QueryContext(ctx, query, key)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar transação da chave de idempotência '%s' no DB: %w", key, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	record, err := scanTransaction(rows)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// ListTransactionsByStatus lista, por conta e nonce, as transações em algum dos estados informados
+func (c *SQLDBClient) ListTransactionsByStatus(ctx context.Context, statuses []string) ([]TransactionRecord, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE status = ANY($1) ORDER BY from_address, nonce, created_at`
+	rows, err := c.db.QueryContext(ctx, query, pq.Array(statuses))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar transações no DB: %w", err)
+	}
+	defer rows.Close()
+
+	var records []TransactionRecord
+	for rows.Next() {
+		record, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler transações do DB: %w", err)
+	}
+	return records, nil
+}
+
+func scanTransaction(rows *sql.Rows) (TransactionRecord, error) {
+	var record TransactionRecord
+	var nonce, gasLimit int64
+	var txType int16
+	var toAddress, gasPrice, gasTipCap, gasFeeCap, method, requester, idempotencyKey sql.NullString
+	var blockHash, replacedBy, replaces sql.NullString
+	var blockNumber sql.NullInt64
+	var value string
+
+	err := rows.Scan(&record.TxHash, &record.FromAddress, &toAddress, &nonce, &txType, &record.RawTx, &gasLimit,
+		&gasPrice, &gasTipCap, &gasFeeCap, &value, &method, &requester, &idempotencyKey, &record.Status,
+		&blockNumber, &blockHash, &replacedBy, &replaces, &record.CreatedAt, &record.UpdatedAt)
+	if err != nil {
+		return TransactionRecord{}, fmt.Errorf("erro ao ler transação do DB: %w", err)
+	}
+
+	record.ToAddress = toAddress.String
+	record.Nonce = uint64(nonce)
+	record.TxType = uint8(txType)
+	record.GasLimit = uint64(gasLimit)
+	record.Method = method.String
+	record.Requester = requester.String
+	record.IdempotencyKey = idempotencyKey.String
+	record.BlockHash = blockHash.String
+	record.ReplacedBy = replacedBy.String
+	record.Replaces = replaces.String
+	if blockNumber.Valid {
+		number := uint64(blockNumber.Int64)
+		record.BlockNumber = &number
+	}
+
+	for _, field := range []struct {
+		raw    sql.NullString
+		target **big.Int
+	}{
+		{gasPrice, &record.GasPrice},
+		{gasTipCap, &record.GasTipCap},
+		{gasFeeCap, &record.GasFeeCap},
+		{sql.NullString{String: value, Valid: true}, &record.Value},
+	} {
+		if !field.raw.Valid {
+			continue
+		}
+		parsed, ok := new(big.Int).SetString(field.raw.String, 10)
+		if !ok {
+			return TransactionRecord{}, fmt.Errorf("erro ao converter valor '%s' da transação %s para big.Int", field.raw.String, record.TxHash)
+		}
+		*field.target = parsed
+	}
+	return record, nil
+}
+
+func nullIfNil(value *big.Int) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value.String()
+}
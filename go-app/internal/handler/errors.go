@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
+)
+
+// RevertResponse descreve, no corpo da resposta, o erro retornado pelo contrato
+type RevertResponse struct {
+	Type      string                  `json:"type"` // "error", "panic" ou "custom"
+	Name      string                  `json:"name,omitempty"`
+	Signature string                  `json:"signature,omitempty"`
+	Args      []contract.MethodOutput `json:"args"`
+	Data      string                  `json:"data,omitempty"`
+}
+
+// writeContractError responde uma falha de chamada ao contrato. Reverts são respondidos com 422 e o erro
+// decodificado em JSON (nome e argumentos), para que o cliente distinga "o contrato recusou" de "o nó está fora";
+// erros de validação viram 400/403/404/409/422, a falta de conta com saldo 503, um envio sem resposta do nó 504 e as demais falhas 500.
+func writeContractError(w http.ResponseWriter, message string, err error) {
+	if revert, ok := revertResponse(err); ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":  fmt.Sprintf("%s: %v", message, err),
+			"revert": revert,
+		})
+		return
+	}
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, contract.ErrMethodNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, contract.ErrMethodNotView),
+		errors.Is(err, contract.ErrMethodNotMutating),
+		errors.Is(err, contract.ErrMethodNotPayable),
+		errors.Is(err, contract.ErrInvalidArguments),
+		errors.Is(err, contract.ErrInvalidRawTx),
+		errors.Is(err, contract.ErrChainIDMismatch),
+		errors.Is(err, contract.ErrNonceGap):
+		status = http.StatusBadRequest
+	case errors.Is(err, contract.ErrMethodNotAllowed):
+		status = http.StatusForbidden
+	case errors.Is(err, contract.ErrNonceTooLow):
+		status = http.StatusConflict
+	case errors.Is(err, contract.ErrGasAboveCeiling),
+		errors.Is(err, contract.ErrGasLimitTooLow):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, contract.ErrNoFundedTransactor):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, contract.ErrSendOutcomeUnknown):
+		status = http.StatusGatewayTimeout
+	}
+	http.Error(w, fmt.Sprintf("%s: %v", message, err), status)
+}
+
+// revertResponse converte os erros tipados de revert do pacote contract no corpo da resposta
+func revertResponse(err error) (RevertResponse, bool) {
+	var customErr *contract.CustomError
+	if errors.As(err, &customErr) {
+		return RevertResponse{
+			Type:      "custom",
+			Name:      customErr.Name,
+			Signature: customErr.Signature,
+			Args:      customErr.Args,
+			Data:      hexutil.Encode(customErr.Data),
+		}, true
+	}
+
+	var panicErr *contract.PanicError
+	if errors.As(err, &panicErr) {
+		return RevertResponse{
+			Type:      "panic",
+			Name:      "Panic",
+			Signature: "Panic(uint256)",
+			Args: []contract.MethodOutput{
+				{Name: "code", Type: "uint256", Value: panicErr.Code.String()},
+				{Name: "description", Type: "string", Value: panicErr.Description},
+			},
+			Data: hexutil.Encode(panicErr.Data),
+		}, true
+	}
+
+	var revertErr *contract.RevertError
+	if errors.As(err, &revertErr) {
+		response := RevertResponse{Type: "error", Args: []contract.MethodOutput{}}
+		if revertErr.Reason != "" {
+			response.Name = "Error"
+			response.Signature = "Error(string)"
+			response.Args = []contract.MethodOutput{{Name: "reason", Type: "string", Value: revertErr.Reason}}
+		}
+		if len(revertErr.Data) > 0 {
+			response.Data = hexutil.Encode(revertErr.Data)
+		}
+		return response, true
+	}
+	return RevertResponse{}, false
+}
@@ -3,40 +3,142 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
+	"github.com/vmm2136/besu_challenge/go-app/internal/database"
+	"github.com/vmm2136/besu_challenge/go-app/internal/pkg/ethutils"
 	"github.com/vmm2136/besu_challenge/go-app/internal/service"
 )
 
-// Handler lida com as requisições HTTP para o contrato
+// Handler lida com as requisições HTTP para os contratos do registro
 type Handler struct {
-	contractService service.ContractService
+	registry    *service.Registry
+	idempotency service.IdempotencyService
+	rawTx       service.RawTxService
+	signatures  service.SignatureService
 }
 
 // NewHandler cria um novo Handler
-func NewHandler(svc service.ContractService) *Handler {
+func NewHandler(registry *service.Registry, idempotency service.IdempotencyService, rawTx service.RawTxService, signatures service.SignatureService) *Handler {
 	return &Handler{
-		contractService: svc,
+		registry:    registry,
+		idempotency: idempotency,
+		rawTx:       rawTx,
+		signatures:  signatures,
 	}
 }
 
-// GetValueHandler lida com a requisição GET /value
+// resolveService retorna o serviço do contrato da rota /contracts/{name} ou, nas rotas sem nome, o contrato padrão.
+// Se o contrato não existir, responde 404 e retorna false.
+func (h *Handler) resolveService(w http.ResponseWriter, r *http.Request) (service.ContractService, bool) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		name = h.registry.DefaultName()
+	}
+
+	svc, ok := h.registry.Get(name)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Contrato '%s' não encontrado", name), http.StatusNotFound)
+		return nil, false
+	}
+	return svc, true
+}
+
+// txContext anexa ao contexto da requisição os dados gravados no journal junto com as transações enviadas
+func txContext(r *http.Request) context.Context {
+	return contract.WithTxMetadata(r.Context(), contract.TxMetadata{
+		Requester:      r.RemoteAddr,
+		IdempotencyKey: r.Header.Get(IdempotencyKeyHeader),
+	})
+}
+
+// resolveValueService é como resolveService, mas exige que o contrato possua as funções 'get' e 'set'
+func (h *Handler) resolveValueService(w http.ResponseWriter, r *http.Request) (service.ContractService, bool) {
+	svc, ok := h.resolveService(w, r)
+	if !ok {
+		return nil, false
+	}
+	if !svc.SupportsValue() {
+		http.Error(w, fmt.Sprintf("Contrato '%s' não possui as funções 'get' e 'set'", svc.ContractName()), http.StatusBadRequest)
+		return nil, false
+	}
+	return svc, true
+}
+
+// ListContractsHandler lida com a requisição GET /contracts
+func (h *Handler) ListContractsHandler(w http.ResponseWriter, r *http.Request) {
+	contracts := make([]map[string]interface{}, 0)
+	for _, name := range h.registry.Names() {
+		svc, _ := h.registry.Get(name)
+		contracts = append(contracts, map[string]interface{}{
+			"name":           name,
+			"address":        svc.ContractAddress().Hex(),
+			"supports_value": svc.SupportsValue(),
+			"default":        name == h.registry.DefaultName(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"contracts": contracts})
+}
+
+// GetValueHandler lida com a requisição GET /value (ou GET /contracts/{name}/value).
+// ?block= lê o valor no estado de um bloco: número (decimal ou 0x), hash ou tag (latest, safe, finalized, pending).
 func (h *Handler) GetValueHandler(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.resolveValueService(w, r)
+	if !ok {
+		return
+	}
+
+	ref, err := contract.ParseBlockRef(r.URL.Query().Get("block"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Parâmetro 'block' inválido: %v", err), http.StatusBadRequest)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	value, transactorAddress, err := h.contractService.GetCurrentValue(ctx)
+	value, block, err := svc.GetCurrentValue(ctx, ref)
+	if errors.Is(err, contract.ErrBlockNotFound) {
+		http.Error(w, fmt.Sprintf("Bloco %s não encontrado", ref), http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Erro ao obter valor do contrato: %v", err), http.StatusInternalServerError)
 		return
 	}
 
+	transactors := make([]map[string]interface{}, 0)
+	for _, account := range svc.Transactors(ctx) {
+		transactor := map[string]interface{}{
+			"address": account.Address.Hex(),
+			"balance": nil,
+			"pending": account.Pending,
+			"funded":  account.Funded,
+		}
+		if account.Balance != nil {
+			transactor["balance"] = account.Balance.String()
+		}
+		transactors = append(transactors, transactor)
+	}
+
 	response := map[string]interface{}{
-		"current_value":      value.String(),
-		"transactor_address": transactorAddress.Hex(),
+		"current_value": value.String(),
+		"transactors":   transactors,
+		"block_number":  block.Number,
+		"block_hash":    block.Hash.Hex(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -44,49 +146,107 @@ func (h *Handler) GetValueHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // SetValueRequest representa o corpo da requisição POST /value.
+// O valor pode ser uma string decimal, uma string hexadecimal com prefixo 0x ou um número JSON inteiro.
 type SetValueRequest struct {
-	Value int64 `json:"value"`
+	Value json.RawMessage `json:"value"`
 }
 
-// SetValueHandler lida com a requisição POST /value
+// ParseValue converte o campo value em um inteiro dentro do intervalo de um uint256
+func (req SetValueRequest) ParseValue() (*big.Int, error) {
+	raw := strings.TrimSpace(string(req.Value))
+	if raw == "" || raw == "null" {
+		return nil, fmt.Errorf("campo 'value' é obrigatório")
+	}
+
+	if strings.HasPrefix(raw, `"`) {
+		var str string
+		if err := json.Unmarshal(req.Value, &str); err != nil {
+			return nil, fmt.Errorf("campo 'value' inválido: %w", err)
+		}
+		return ethutils.ParseUint256(str)
+	}
+
+	if strings.HasPrefix(raw, "0x") || strings.HasPrefix(raw, "0X") {
+		return nil, fmt.Errorf("valores hexadecimais devem ser enviados como string")
+	}
+	return ethutils.ParseUint256(raw)
+}
+
+// SetValueHandler lida com a requisição POST /value (ou POST /contracts/{name}/value).
+// Com ?wait=true a resposta só é enviada depois que a transação for minerada (ou o tempo limite expirar).
 func (h *Handler) SetValueHandler(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.resolveValueService(w, r)
+	if !ok {
+		return
+	}
+
 	var req SetValueRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, fmt.Sprintf("Payload inválido: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	if req.Value < 0 {
-		http.Error(w, "O valor não pode ser negativo", http.StatusBadRequest)
+	value, err := req.ParseValue()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Valor inválido: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	wait := r.URL.Query().Get("wait") == "true"
+	timeout := 10 * time.Second
+	if wait {
+		timeout = 60 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(txContext(r), timeout)
 	defer cancel()
 
-	txHash, err := h.contractService.SetNewValue(ctx, req.Value)
+	txHash, err := svc.SetNewValue(ctx, value)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Erro ao definir valor no contrato: %v", err), http.StatusInternalServerError)
+		writeContractError(w, "Erro ao definir valor no contrato", err)
 		return
 	}
 
-	response := map[string]string{
+	response := map[string]interface{}{
 		"message":   "Transação enviada com sucesso",
 		"tx_hash":   txHash.Hex(),
-		"new_value": strconv.FormatInt(req.Value, 10),
+		"new_value": value.String(),
+		"status":    contract.TxStateSubmitted,
+	}
+	statusCode := http.StatusAccepted
+
+	if wait {
+		status, err := svc.WaitForTransaction(ctx, txHash)
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			http.Error(w, fmt.Sprintf("Erro ao aguardar transação %s: %v", txHash.Hex(), err), http.StatusInternalServerError)
+			return
+		}
+		if status != nil {
+			response["status"] = status.State
+			response["block_number"] = status.BlockNumber
+			response["confirmations"] = status.Confirmations
+			if status.State != contract.TxStateSubmitted && status.State != contract.TxStatePending {
+				statusCode = http.StatusOK
+			}
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
+	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(response)
 }
 
-// SyncValueHandler lida com a requisição POST /sync
+// SyncValueHandler lida com a requisição POST /sync (ou POST /contracts/{name}/sync)
 func (h *Handler) SyncValueHandler(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.resolveValueService(w, r)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
 
-	networkValue, dbValue, err := h.contractService.SyncContractValue(ctx)
+	networkValue, dbValue, block, err := svc.SyncContractValue(ctx)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Erro ao sincronizar valor do contrato: %v", err), http.StatusInternalServerError)
 		return
@@ -96,6 +256,7 @@ func (h *Handler) SyncValueHandler(w http.ResponseWriter, r *http.Request) {
 		"message":        "Sincronização concluída",
 		"network_value":  networkValue.String(),
 		"database_value": dbValue.String(),
+		"synced_block":   strconv.FormatUint(block, 10),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -103,12 +264,54 @@ func (h *Handler) SyncValueHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// CheckValueHandler lida com a requisição GET /check
+// SyncStatusHandler lida com a requisição GET /sync/status (ou GET /contracts/{name}/sync/status)
+func (h *Handler) SyncStatusHandler(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.resolveService(w, r)
+	if !ok {
+		return
+	}
+
+	response := map[string]interface{}{
+		"enabled": false,
+	}
+
+	if syncWorker := h.registry.SyncWorker(svc.ContractName()); syncWorker != nil {
+		status := syncWorker.Status()
+		response = map[string]interface{}{
+			"enabled":              status.Enabled,
+			"mode":                 status.Mode,
+			"running":              status.Running,
+			"last_run_at":          status.LastRunAt,
+			"last_success_at":      status.LastSuccessAt,
+			"last_error":           status.LastError,
+			"consecutive_failures": status.ConsecutiveFailures,
+			"network_value":        status.NetworkValue,
+			"database_value":       status.DatabaseValue,
+			"synced_block":         status.SyncedBlock,
+			"head_block":           status.HeadBlock,
+			"lag_blocks":           status.LagBlocks(),
+		}
+		if !status.LastSuccessAt.IsZero() {
+			response["lag_seconds"] = time.Since(status.LastSuccessAt).Seconds()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// CheckValueHandler lida com a requisição GET /check (ou GET /contracts/{name}/check)
 func (h *Handler) CheckValueHandler(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.resolveValueService(w, r)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	areEqual, networkValue, dbValue, err := h.contractService.CheckContractValue(ctx)
+	areEqual, networkValue, dbValue, err := svc.CheckContractValue(ctx)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Erro ao verificar valor do contrato: %v", err), http.StatusInternalServerError)
 		return
@@ -125,3 +328,231 @@ func (h *Handler) CheckValueHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// GetTransactionHandler lida com a requisição GET /tx/{hash}
+func (h *Handler) GetTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.resolveService(w, r)
+	if !ok {
+		return
+	}
+
+	hashParam := chi.URLParam(r, "hash")
+	hash, ok := parseTxHash(w, hashParam)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	status, err := svc.GetTransactionStatus(ctx, hash)
+	if errors.Is(err, contract.ErrTxNotFound) {
+		http.Error(w, fmt.Sprintf("Transação %s não encontrada", hashParam), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Erro ao consultar transação: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"tx_hash":       status.Hash.Hex(),
+		"status":        status.State,
+		"block_number":  status.BlockNumber,
+		"block_hash":    status.BlockHash.Hex(),
+		"gas_used":      status.GasUsed,
+		"confirmations": status.Confirmations,
+	}
+	if !status.SubmittedAt.IsZero() {
+		response["from"] = status.From.Hex()
+		response["nonce"] = status.Nonce
+		response["submitted_at"] = status.SubmittedAt
+		response["updated_at"] = status.UpdatedAt
+	}
+	if status.ReplacedBy != (common.Hash{}) {
+		response["replaced_by"] = status.ReplacedBy.Hex()
+	}
+	if status.Replaces != (common.Hash{}) {
+		response["replaces"] = status.Replaces.Hex()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// SpeedUpTransactionHandler lida com a requisição POST /tx/{hash}/speedup
+func (h *Handler) SpeedUpTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	h.replaceTransaction(w, r, "speedup", service.ContractService.SpeedUpTransaction)
+}
+
+// CancelTransactionHandler lida com a requisição POST /tx/{hash}/cancel
+func (h *Handler) CancelTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	h.replaceTransaction(w, r, "cancel", service.ContractService.CancelTransaction)
+}
+
+// replaceTransaction executa o speedup ou o cancelamento de uma transação pendente enviada pela API
+func (h *Handler) replaceTransaction(w http.ResponseWriter, r *http.Request, action string,
+	replace func(service.ContractService, context.Context, common.Hash) (common.Hash, error)) {
+	svc, ok := h.resolveService(w, r)
+	if !ok {
+		return
+	}
+
+	hashParam := chi.URLParam(r, "hash")
+	hash, ok := parseTxHash(w, hashParam)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(txContext(r), 15*time.Second)
+	defer cancel()
+
+	newHash, err := replace(svc, ctx, hash)
+	switch {
+	case errors.Is(err, contract.ErrTxNotFound):
+		http.Error(w, fmt.Sprintf("Transação %s não foi enviada por esta API", hashParam), http.StatusNotFound)
+		return
+	case errors.Is(err, contract.ErrTxNotReplaceable):
+		http.Error(w, fmt.Sprintf("Erro ao substituir transação: %v", err), http.StatusConflict)
+		return
+	case errors.Is(err, contract.ErrFeeAboveCeiling):
+		http.Error(w, fmt.Sprintf("Erro ao substituir transação: %v", err), http.StatusUnprocessableEntity)
+		return
+	case err != nil:
+		http.Error(w, fmt.Sprintf("Erro ao substituir transação: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":          "Transação substituta enviada com sucesso",
+		"action":           action,
+		"original_tx_hash": hash.Hex(),
+		"tx_hash":          newHash.Hex(),
+		"status":           contract.TxStateSubmitted,
+	})
+}
+
+// parseTxHash valida o hash de transação recebido na URL; se for inválido, responde 400 e retorna false
+func parseTxHash(w http.ResponseWriter, hashParam string) (common.Hash, bool) {
+	decoded, err := hexutil.Decode(hashParam)
+	if err != nil || len(decoded) != common.HashLength {
+		http.Error(w, "Hash de transação inválido: esperado 32 bytes em hexadecimal com prefixo 0x", http.StatusBadRequest)
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(decoded), true
+}
+
+// Limites de paginação do GET /history
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 500
+)
+
+// GetHistoryHandler lida com a requisição GET /history (ou GET /contracts/{name}/history).
+// Filtros opcionais: from_time, to_time (RFC3339), from_block, to_block, setter, limit e offset.
+func (h *Handler) GetHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.resolveValueService(w, r)
+	if !ok {
+		return
+	}
+
+	filter, err := parseHistoryFilter(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Parâmetros inválidos: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	entries, total, err := svc.GetValueHistory(ctx, filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Erro ao obter histórico: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		item := map[string]interface{}{
+			"id":           entry.ID,
+			"source":       entry.Source,
+			"block_number": entry.BlockNumber,
+			"block_hash":   entry.BlockHash,
+			"tx_hash":      entry.TxHash,
+			"log_index":    entry.LogIndex,
+			"setter":       entry.Setter,
+			"old_value":    nil,
+			"new_value":    entry.NewValue.String(),
+			"created_at":   entry.CreatedAt,
+		}
+		if entry.OldValue != nil {
+			item["old_value"] = entry.OldValue.String()
+		}
+		items = append(items, item)
+	}
+
+	response := map[string]interface{}{
+		"items":  items,
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseHistoryFilter lê os filtros do GET /history a partir da query string
+func parseHistoryFilter(r *http.Request) (database.HistoryFilter, error) {
+	query := r.URL.Query()
+	filter := database.HistoryFilter{Limit: defaultHistoryLimit}
+
+	if setter := query.Get("setter"); setter != "" {
+		if !common.IsHexAddress(setter) {
+			return filter, fmt.Errorf("setter '%s' não é um endereço válido", setter)
+		}
+		filter.Setter = setter
+	}
+
+	for name, target := range map[string]**time.Time{"from_time": &filter.FromTime, "to_time": &filter.ToTime} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, fmt.Errorf("%s deve estar no formato RFC3339: %w", name, err)
+			}
+			*target = &parsed
+		}
+	}
+
+	for name, target := range map[string]**uint64{"from_block": &filter.FromBlock, "to_block": &filter.ToBlock} {
+		if value := query.Get(name); value != "" {
+			parsed, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return filter, fmt.Errorf("%s deve ser um número de bloco: %w", name, err)
+			}
+			*target = &parsed
+		}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxHistoryLimit {
+			return filter, fmt.Errorf("limit deve estar entre 1 e %d", maxHistoryLimit)
+		}
+		filter.Limit = limit
+	}
+
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return filter, fmt.Errorf("offset deve ser um inteiro não negativo")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/vmm2136/besu_challenge/go-app/internal/database"
+)
+
+func TestParseTxHash(t *testing.T) {
+	tests := []struct {
+		name   string
+		hash   string
+		wantOK bool
+	}{
+		{name: "hash válido", hash: "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b", wantOK: true},
+		{name: "maiúsculas", hash: "0x88DF016429689C079F3B2F6AD39FA052532C56795B733DA78A91EBE6A713944B", wantOK: true},
+		{name: "sem prefixo", hash: "88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"},
+		{name: "caracteres não hexadecimais", hash: "0xzzdf016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"},
+		{name: "curto", hash: "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a71394"},
+		{name: "longo", hash: "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b00"},
+		{name: "vazio", hash: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			hash, ok := parseTxHash(w, tt.hash)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, esperado %v", ok, tt.wantOK)
+			}
+			if !ok {
+				if w.Code != http.StatusBadRequest {
+					t.Errorf("status %d, esperado %d", w.Code, http.StatusBadRequest)
+				}
+				return
+			}
+			if hash != common.HexToHash(tt.hash) {
+				t.Errorf("hash %s, esperado %s", hash.Hex(), tt.hash)
+			}
+		})
+	}
+}
+
+func TestParseHistoryFilter(t *testing.T) {
+	block := func(n uint64) *uint64 { return &n }
+	at := func(value string) *time.Time {
+		parsed, _ := time.Parse(time.RFC3339, value)
+		return &parsed
+	}
+	const setter = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+
+	tests := []struct {
+		name    string
+		query   string
+		want    database.HistoryFilter
+		wantErr bool
+	}{
+		{name: "sem filtros", query: "", want: database.HistoryFilter{Limit: defaultHistoryLimit}},
+		{
+			name:  "todos os filtros",
+			query: "setter=" + setter + "&from_time=2026-01-01T00:00:00Z&to_time=2026-01-02T12:00:00-03:00&from_block=10&to_block=20&limit=5&offset=15",
+			want: database.HistoryFilter{
+				Setter:    setter,
+				FromTime:  at("2026-01-01T00:00:00Z"),
+				ToTime:    at("2026-01-02T12:00:00-03:00"),
+				FromBlock: block(10),
+				ToBlock:   block(20),
+				Limit:     5,
+				Offset:    15,
+			},
+		},
+		{name: "limite máximo", query: "limit=500", want: database.HistoryFilter{Limit: maxHistoryLimit}},
+		{name: "bloco zero", query: "from_block=0", want: database.HistoryFilter{FromBlock: block(0), Limit: defaultHistoryLimit}},
+		{name: "setter inválido", query: "setter=0x123", wantErr: true},
+		{name: "data fora do RFC3339", query: "from_time=2026-01-01", wantErr: true},
+		{name: "bloco negativo", query: "to_block=-1", wantErr: true},
+		{name: "bloco não numérico", query: "from_block=latest", wantErr: true},
+		{name: "limit zero", query: "limit=0", wantErr: true},
+		{name: "limit acima do máximo", query: "limit=501", wantErr: true},
+		{name: "offset negativo", query: "offset=-1", wantErr: true},
+		{name: "offset não numérico", query: "offset=abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/history?"+tt.query, nil)
+			filter, err := parseHistoryFilter(r)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("esperado erro, recebido %+v", filter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseHistoryFilter: %v", err)
+			}
+			if !reflect.DeepEqual(filter, tt.want) {
+				t.Errorf("filtro %+v, esperado %+v", filter, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/vmm2136/besu_challenge/go-app/internal/service"
+)
+
+const (
+	// IdempotencyKeyHeader é o cabeçalho com a chave de idempotência escolhida pelo cliente
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader marca as respostas devolvidas a partir de uma requisição anterior
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// Idempotent executa a requisição uma única vez por Idempotency-Key. Repetições com a mesma chave e o mesmo
+// corpo recebem a resposta original; a mesma chave com outro corpo (ou ainda em andamento) recebe 409.
+// Respostas 2xx são gravadas; uma resposta de erro também é, na forma da transação enviada, se a requisição chegou a
+// enviar uma transação com a chave. Só erros anteriores ao envio liberam a chave para o cliente repetir a requisição.
+// Requisições sem o cabeçalho seguem sem alteração.
+func (h *Handler) Idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			http.Error(w, fmt.Sprintf("%s deve ter no máximo %d caracteres", IdempotencyKeyHeader, maxIdempotencyKeyLength), http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Erro ao ler payload: %v", err), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		replay, err := h.idempotency.Begin(ctx, key, requestHash(r, body))
+		cancel()
+		switch {
+		case errors.Is(err, service.ErrIdempotencyKeyConflict), errors.Is(err, service.ErrIdempotencyKeyInProgress):
+			http.Error(w, fmt.Sprintf("%s '%s': %v", IdempotencyKeyHeader, key, err), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, fmt.Sprintf("Erro ao verificar %s: %v", IdempotencyKeyHeader, err), http.StatusInternalServerError)
+			return
+		case replay != nil:
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(idempotentReplayedHeader, "true")
+			w.WriteHeader(replay.StatusCode)
+			w.Write(replay.Body)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// a resposta é gravada mesmo que o cliente desconecte antes do fim da requisição. Se a gravação falhar
+		// a chave continua reservada, e a repetição só a assume depois do tempo limite, conferindo o journal.
+		storeCtx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 5*time.Second)
+		defer cancel()
+		response := service.IdempotentResponse{
+			StatusCode: recorder.statusCode,
+			Body:       recorder.body.Bytes(),
+			TxHash:     txHashFromResponse(recorder.body.Bytes()),
+		}
+		if err := h.idempotency.Finish(storeCtx, key, response); err != nil {
+			fmt.Printf("Erro ao gravar resposta de %s '%s': %v\n", IdempotencyKeyHeader, key, err)
+		}
+	})
+}
+
+// responseRecorder repassa a resposta ao cliente e guarda uma cópia do status e do corpo
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	rr.statusCode = statusCode
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+// requestHash identifica a requisição pela rota e pelo corpo. Corpos JSON são compactados antes,
+// para que diferenças de espaçamento não sejam tratadas como outra requisição.
+func requestHash(r *http.Request, body []byte) string {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, body); err == nil {
+		body = compacted.Bytes()
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s?%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// txHashFromResponse extrai o campo tx_hash das respostas dos endpoints que enviam transações
+func txHashFromResponse(body []byte) string {
+	var response struct {
+		TxHash string `json:"tx_hash"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	return response.TxHash
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-chi/chi/v5"
+	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
+	"github.com/vmm2136/besu_challenge/go-app/internal/service"
+)
+
+// MethodRequest é o corpo das chamadas genéricas: args na ordem do ABI e, para /transact e /build, o value em wei.
+// From é a conta que vai assinar a transação montada por /build.
+type MethodRequest struct {
+	Args  []json.RawMessage `json:"args"`
+	Value json.RawMessage   `json:"value"`
+	From  string            `json:"from"`
+}
+
+// ParseValue converte o campo value (opcional, padrão 0) em wei
+func (req MethodRequest) ParseValue() (*big.Int, error) {
+	raw := strings.TrimSpace(string(req.Value))
+	if raw == "" || raw == "null" {
+		return big.NewInt(0), nil
+	}
+	return SetValueRequest{Value: req.Value}.ParseValue()
+}
+
+// decodeMethodRequest lê o corpo da requisição; um corpo vazio equivale a uma chamada sem argumentos
+func decodeMethodRequest(r *http.Request) (MethodRequest, error) {
+	var req MethodRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return MethodRequest{}, err
+	}
+	return req, nil
+}
+
+// ListMethodsHandler lida com a requisição GET /contracts/{name}/methods
+func (h *Handler) ListMethodsHandler(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.resolveService(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"contract": svc.ContractName(),
+		"methods":  svc.ListMethods(),
+	})
+}
+
+// CallMethodHandler lida com a requisição POST /contracts/{name}/call/{method} (funções view/pure)
+func (h *Handler) CallMethodHandler(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.resolveService(w, r)
+	if !ok {
+		return
+	}
+	method := chi.URLParam(r, "method")
+
+	req, err := decodeMethodRequest(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Payload inválido: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	outputs, err := svc.CallMethod(ctx, method, req.Args)
+	if err != nil {
+		writeContractError(w, fmt.Sprintf("Erro ao chamar função '%s'", method), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"contract": svc.ContractName(),
+		"method":   method,
+		"outputs":  outputs,
+	})
+}
+
+// TransactMethodHandler lida com a requisição POST /contracts/{name}/transact/{method} (funções que alteram o estado).
+// Assim como POST /value, aceita ?wait=true para responder só depois que a transação for minerada.
+func (h *Handler) TransactMethodHandler(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.resolveService(w, r)
+	if !ok {
+		return
+	}
+	method := chi.URLParam(r, "method")
+
+	req, err := decodeMethodRequest(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Payload inválido: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	value, err := req.ParseValue()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Valor inválido: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	wait := r.URL.Query().Get("wait") == "true"
+	timeout := 10 * time.Second
+	if wait {
+		timeout = 60 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(txContext(r), timeout)
+	defer cancel()
+
+	txHash, err := svc.TransactMethod(ctx, method, req.Args, value)
+	if err != nil {
+		writeContractError(w, fmt.Sprintf("Erro ao executar função '%s'", method), err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"message":  "Transação enviada com sucesso",
+		"contract": svc.ContractName(),
+		"method":   method,
+		"tx_hash":  txHash.Hex(),
+		"status":   contract.TxStateSubmitted,
+	}
+	statusCode := http.StatusAccepted
+
+	if wait {
+		status, err := svc.WaitForTransaction(ctx, txHash)
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			http.Error(w, fmt.Sprintf("Erro ao aguardar transação %s: %v", txHash.Hex(), err), http.StatusInternalServerError)
+			return
+		}
+		if status != nil {
+			response["status"] = status.State
+			response["block_number"] = status.BlockNumber
+			response["confirmations"] = status.Confirmations
+			if status.State != contract.TxStateSubmitted && status.State != contract.TxStatePending {
+				statusCode = http.StatusOK
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
+
+// BuildMethodHandler lida com a requisição POST /contracts/{name}/build/{method}: monta, sem assinar, a transação
+// de from para a função, para ser assinada pela carteira do cliente e enviada em POST /tx/raw
+func (h *Handler) BuildMethodHandler(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.resolveService(w, r)
+	if !ok {
+		return
+	}
+	method := chi.URLParam(r, "method")
+
+	req, err := decodeMethodRequest(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Payload inválido: %v", err), http.StatusBadRequest)
+		return
+	}
+	if !common.IsHexAddress(req.From) {
+		http.Error(w, "Campo 'from' deve ser um endereço hexadecimal", http.StatusBadRequest)
+		return
+	}
+
+	value, err := req.ParseValue()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Valor inválido: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	built, err := svc.BuildTransaction(ctx, method, req.Args, value, common.HexToAddress(req.From))
+	if errors.Is(err, service.ErrManagedSender) {
+		http.Error(w, fmt.Sprintf("Erro ao montar transação para a função '%s': %v", method, err), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		writeContractError(w, fmt.Sprintf("Erro ao montar transação para a função '%s'", method), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"contract":     svc.ContractName(),
+		"method":       method,
+		"tx":           built.Tx,
+		"signing_hash": built.SigningHash.Hex(),
+	})
+}
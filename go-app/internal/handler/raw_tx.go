@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
+	"github.com/vmm2136/besu_challenge/go-app/internal/service"
+)
+
+// RawTxRequest é o corpo de POST /tx/raw: a transação assinada pelo cliente, em hexadecimal
+// (o mesmo parâmetro de eth_sendRawTransaction)
+type RawTxRequest struct {
+	Raw string `json:"raw"`
+}
+
+// SendRawTransactionHandler lida com a requisição POST /tx/raw.
+// O contrato é identificado pelo destino da transação; assim como POST /value, aceita ?wait=true.
+func (h *Handler) SendRawTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	var req RawTxRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Payload inválido: %v", err), http.StatusBadRequest)
+		return
+	}
+	raw, err := hexutil.Decode(req.Raw)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Transação inválida: campo 'raw' deve ser hexadecimal com prefixo 0x: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	wait := r.URL.Query().Get("wait") == "true"
+	timeout := 10 * time.Second
+	if wait {
+		timeout = 60 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(txContext(r), timeout)
+	defer cancel()
+
+	result, err := h.rawTx.Submit(ctx, raw)
+	if errors.Is(err, service.ErrUnknownContract) {
+		http.Error(w, fmt.Sprintf("Erro ao enviar transação assinada: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, service.ErrManagedSender) {
+		http.Error(w, fmt.Sprintf("Erro ao enviar transação assinada: %v", err), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		writeContractError(w, "Erro ao enviar transação assinada", err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"message":  "Transação enviada com sucesso",
+		"contract": result.Contract,
+		"method":   result.Method,
+		"from":     result.From.Hex(),
+		"nonce":    result.Nonce,
+		"tx_hash":  result.Hash.Hex(),
+		"status":   contract.TxStateSubmitted,
+	}
+	statusCode := http.StatusAccepted
+
+	if svc, ok := h.registry.Get(result.Contract); wait && ok {
+		status, err := svc.WaitForTransaction(ctx, result.Hash)
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			http.Error(w, fmt.Sprintf("Erro ao aguardar transação %s: %v", result.Hash.Hex(), err), http.StatusInternalServerError)
+			return
+		}
+		if status != nil {
+			response["status"] = status.State
+			response["block_number"] = status.BlockNumber
+			response["confirmations"] = status.Confirmations
+			if status.State != contract.TxStateSubmitted && status.State != contract.TxStatePending {
+				statusCode = http.StatusOK
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/vmm2136/besu_challenge/go-app/internal/pkg/ethutils"
+	"github.com/vmm2136/besu_challenge/go-app/internal/service"
+)
+
+// MessageRequest é o corpo de /sign/message e /verify/message. A mensagem vem em message (texto UTF-8)
+// ou em data (bytes em hexadecimal com 0x); address é a conta que assina ou, na verificação, a conta esperada.
+type MessageRequest struct {
+	Message   *string `json:"message"`
+	Data      string  `json:"data"`
+	Address   string  `json:"address"`
+	Signature string  `json:"signature"`
+}
+
+// ParseMessage retorna os bytes da mensagem, exigindo exatamente um entre message e data
+func (req MessageRequest) ParseMessage() ([]byte, error) {
+	switch {
+	case req.Message != nil && req.Data != "":
+		return nil, fmt.Errorf("informe 'message' ou 'data', não ambos")
+	case req.Message != nil:
+		return []byte(*req.Message), nil
+	case req.Data != "":
+		data, err := hexutil.Decode(req.Data)
+		if err != nil {
+			return nil, fmt.Errorf("campo 'data' deve ser hexadecimal com prefixo 0x: %w", err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("campo 'message' ou 'data' é obrigatório")
+}
+
+// TypedDataRequest é o corpo de /sign/typed-data e /verify/typed-data, com os dados no formato de eth_signTypedData_v4
+type TypedDataRequest struct {
+	TypedData *apitypes.TypedData `json:"typed_data"`
+	Address   string              `json:"address"`
+	Signature string              `json:"signature"`
+}
+
+// SigningAuth protege os endpoints /sign/*: exige o cabeçalho "Authorization: Bearer <SIGNING_API_TOKEN>" e
+// responde 403 se a assinatura estiver desabilitada ou 401 se o token estiver ausente ou incorreto
+func (h *Handler) SigningAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if err := h.signatures.Authorize(token); err != nil {
+			if errors.Is(err, service.ErrSigningUnauthorized) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			writeSignatureError(w, "Acesso negado", err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SignMessageHandler lida com a requisição POST /sign/message (EIP-191)
+func (h *Handler) SignMessageHandler(w http.ResponseWriter, r *http.Request) {
+	var req MessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Payload inválido: %v", err), http.StatusBadRequest)
+		return
+	}
+	message, err := req.ParseMessage()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Mensagem inválida: %v", err), http.StatusBadRequest)
+		return
+	}
+	address, ok := parseSignerAddress(w, req.Address)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	signature, err := h.signatures.SignMessage(ctx, address, message)
+	if err != nil {
+		writeSignatureError(w, "Erro ao assinar mensagem", err)
+		return
+	}
+	writeSignature(w, signature)
+}
+
+// SignTypedDataHandler lida com a requisição POST /sign/typed-data (EIP-712)
+func (h *Handler) SignTypedDataHandler(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeTypedDataRequest(w, r)
+	if !ok {
+		return
+	}
+	address, ok := parseSignerAddress(w, req.Address)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	signature, err := h.signatures.SignTypedData(ctx, address, *req.TypedData)
+	if err != nil {
+		writeSignatureError(w, "Erro ao assinar dados tipados", err)
+		return
+	}
+	writeSignature(w, signature)
+}
+
+// VerifyMessageHandler lida com a requisição POST /verify/message: recupera a conta que assinou a mensagem
+// e, se address for informado, indica se é ela
+func (h *Handler) VerifyMessageHandler(w http.ResponseWriter, r *http.Request) {
+	var req MessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Payload inválido: %v", err), http.StatusBadRequest)
+		return
+	}
+	message, err := req.ParseMessage()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Mensagem inválida: %v", err), http.StatusBadRequest)
+		return
+	}
+	signature, ok := parseSignature(w, req.Signature)
+	if !ok {
+		return
+	}
+	expected, ok := parseOptionalAddress(w, req.Address)
+	if !ok {
+		return
+	}
+
+	recovered, err := h.signatures.RecoverMessage(message, signature)
+	if err != nil {
+		writeSignatureError(w, "Erro ao verificar assinatura", err)
+		return
+	}
+	writeVerification(w, recovered, expected)
+}
+
+// VerifyTypedDataHandler lida com a requisição POST /verify/typed-data, como /verify/message para dados EIP-712
+func (h *Handler) VerifyTypedDataHandler(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeTypedDataRequest(w, r)
+	if !ok {
+		return
+	}
+	signature, ok := parseSignature(w, req.Signature)
+	if !ok {
+		return
+	}
+	expected, ok := parseOptionalAddress(w, req.Address)
+	if !ok {
+		return
+	}
+
+	recovered, err := h.signatures.RecoverTypedData(*req.TypedData, signature)
+	if err != nil {
+		writeSignatureError(w, "Erro ao verificar assinatura", err)
+		return
+	}
+	writeVerification(w, recovered, expected)
+}
+
+// decodeTypedDataRequest lê o corpo de /sign/typed-data e /verify/typed-data; se for inválido, responde 400
+func decodeTypedDataRequest(w http.ResponseWriter, r *http.Request) (TypedDataRequest, bool) {
+	var req TypedDataRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Payload inválido: %v", err), http.StatusBadRequest)
+		return TypedDataRequest{}, false
+	}
+	if req.TypedData == nil {
+		http.Error(w, "Campo 'typed_data' é obrigatório", http.StatusBadRequest)
+		return TypedDataRequest{}, false
+	}
+	return req, true
+}
+
+// parseOptionalAddress valida um endereço opcional do corpo; vazio retorna o endereço zero
+func parseOptionalAddress(w http.ResponseWriter, address string) (common.Address, bool) {
+	if address == "" {
+		return common.Address{}, true
+	}
+	if !common.IsHexAddress(address) {
+		http.Error(w, "Campo 'address' deve ser um endereço hexadecimal", http.StatusBadRequest)
+		return common.Address{}, false
+	}
+	return common.HexToAddress(address), true
+}
+
+// parseSignerAddress valida a conta que assina, obrigatória em /sign/*; se faltar ou for inválida, responde 400
+func parseSignerAddress(w http.ResponseWriter, address string) (common.Address, bool) {
+	if address == "" {
+		http.Error(w, "Campo 'address' é obrigatório", http.StatusBadRequest)
+		return common.Address{}, false
+	}
+	return parseOptionalAddress(w, address)
+}
+
+// parseSignature valida a assinatura em hexadecimal do corpo; se for inválida, responde 400
+func parseSignature(w http.ResponseWriter, signature string) ([]byte, bool) {
+	decoded, err := hexutil.Decode(signature)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Campo 'signature' deve ser hexadecimal com prefixo 0x: %v", err), http.StatusBadRequest)
+		return nil, false
+	}
+	return decoded, true
+}
+
+// writeSignatureError responde uma falha do serviço de assinaturas: dados inválidos viram 400, token ausente
+// ou incorreto 401, conta fora do pool 404, assinatura desabilitada 403 e as demais falhas 500
+func writeSignatureError(w http.ResponseWriter, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ethutils.ErrInvalidSignature), errors.Is(err, ethutils.ErrInvalidTypedData), errors.Is(err, service.ErrSignerRequired):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrSigningUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, service.ErrUnknownSigner):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrSigningDisabled):
+		status = http.StatusForbidden
+	}
+	http.Error(w, fmt.Sprintf("%s: %v", message, err), status)
+}
+
+func writeSignature(w http.ResponseWriter, signature *service.Signature) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"address":   signature.Address.Hex(),
+		"hash":      signature.Hash.Hex(),
+		"signature": hexutil.Encode(signature.Signature),
+	})
+}
+
+func writeVerification(w http.ResponseWriter, recovered *service.Signature, expected common.Address) {
+	response := map[string]interface{}{
+		"signer": recovered.Address.Hex(),
+		"hash":   recovered.Hash.Hex(),
+	}
+	if expected != (common.Address{}) {
+		response["address"] = expected.Hex()
+		response["valid"] = recovered.Address == expected
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"os"
+	"strings"
 )
 
 // LoadPrivateKeyFromEnv carrega uma chave privada a partir de uma variável de ambiente
@@ -22,6 +23,24 @@ func LoadPrivateKeyFromEnv(envVarName string) (*ecdsa.PrivateKey, error) {
 	return privateKey, nil
 }
 
+// LoadPrivateKeysFromEnv carrega uma lista de chaves privadas separadas por vírgula de uma variável de ambiente.
+// Uma variável ausente ou vazia resulta em uma lista vazia.
+func LoadPrivateKeysFromEnv(envVarName string) ([]*ecdsa.PrivateKey, error) {
+	var keys []*ecdsa.PrivateKey
+	for i, keyHex := range strings.Split(os.Getenv(envVarName), ",") {
+		keyHex = strings.TrimSpace(keyHex)
+		if keyHex == "" {
+			continue
+		}
+		privateKey, err := crypto.HexToECDSA(keyHex)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao converter chave privada %d de %s de HEX: %w", i+1, envVarName, err)
+		}
+		keys = append(keys, privateKey)
+	}
+	return keys, nil
+}
+
 // GetPublicKeyAddress retorna o endereço público de uma chave privada
 func GetPublicKeyAddress(privateKey *ecdsa.PrivateKey) (common.Address, error) {
 	publicKey := privateKey.Public()
@@ -0,0 +1,41 @@
+package ethutils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// LoadKeystoreSigner decifra um arquivo keystore V3 (JSON, como os gerados pelo geth, Besu e Clef)
+// com a senha lida de passphraseFile
+func LoadKeystoreSigner(keystorePath, passphraseFile string) (*PrivateKeySigner, error) {
+	keyJSON, err := os.ReadFile(keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler keystore %s: %w", keystorePath, err)
+	}
+
+	passphrase, err := readSecretFile(passphraseFile)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao decifrar keystore %s: %w", keystorePath, err)
+	}
+	return NewPrivateKeySigner(key.PrivateKey)
+}
+
+// readSecretFile lê um arquivo de segredo, descartando a quebra de linha final
+func readSecretFile(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("caminho do arquivo de segredo não informado")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler arquivo de segredo %s: %w", path, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
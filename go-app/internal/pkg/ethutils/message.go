@@ -0,0 +1,77 @@
+package ethutils
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// signatureLength é o tamanho de uma assinatura secp256k1 no formato r || s || v
+const signatureLength = 65
+
+// Erros da assinatura e verificação de mensagens
+var (
+	ErrInvalidSignature = errors.New("assinatura inválida")
+	ErrInvalidTypedData = errors.New("dados tipados EIP-712 inválidos")
+)
+
+// MessageHash calcula o hash EIP-191 (versão 0x45, o mesmo de personal_sign) de uma mensagem
+func MessageHash(message []byte) common.Hash {
+	return common.BytesToHash(accounts.TextHash(message))
+}
+
+// TypedDataHash calcula o hash EIP-712 dos dados tipados, a partir do domínio e da mensagem
+func TypedDataHash(typedData apitypes.TypedData) (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("%w: %v", ErrInvalidTypedData, err)
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// RecoverSigner recupera o endereço que assinou hash. Aceita v como 0/1 ou 27/28.
+func RecoverSigner(hash common.Hash, signature []byte) (common.Address, error) {
+	if len(signature) != signatureLength {
+		return common.Address{}, fmt.Errorf("%w: esperado %d bytes, recebido %d", ErrInvalidSignature, signatureLength, len(signature))
+	}
+	sig := append([]byte(nil), signature...)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	if sig[64] > 1 {
+		return common.Address{}, fmt.Errorf("%w: v deve ser 0, 1, 27 ou 28", ErrInvalidSignature)
+	}
+
+	publicKey, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// signHash assina hash com a chave e devolve v como 27/28, o formato de personal_sign e eth_signTypedData
+func signHash(hash common.Hash, key *ecdsa.PrivateKey) ([]byte, error) {
+	signature, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+// checkSignature confere que signature de hash foi feita por from
+func checkSignature(hash common.Hash, signature []byte, from common.Address) error {
+	signer, err := RecoverSigner(hash, signature)
+	if err != nil {
+		return err
+	}
+	if signer != from {
+		return fmt.Errorf("%w: assinada por %s, esperado %s", ErrInvalidSignature, signer.Hex(), from.Hex())
+	}
+	return nil
+}
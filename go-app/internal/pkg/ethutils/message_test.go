@@ -0,0 +1,171 @@
+package ethutils
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// mailTypedData é o exemplo "Ether Mail" da especificação do EIP-712
+func mailTypedData() apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Person": {
+				{Name: "name", Type: "string"},
+				{Name: "wallet", Type: "address"},
+			},
+			"Mail": {
+				{Name: "from", Type: "Person"},
+				{Name: "to", Type: "Person"},
+				{Name: "contents", Type: "string"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Ether Mail",
+			Version:           "1",
+			ChainId:           (*math.HexOrDecimal256)(big.NewInt(1)),
+			VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+		},
+		Message: apitypes.TypedDataMessage{
+			"from":     map[string]interface{}{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+			"to":       map[string]interface{}{"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+			"contents": "Hello, Bob!",
+		},
+	}
+}
+
+// mailSignature é a assinatura do exemplo do EIP-712 pela chave keccak256("cow") (conta 0xCD2a...D826), com v = 28
+const mailSignature = "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+
+func TestMessageHash(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		// keccak256("\x19Ethereum Signed Message:\n5hello"), o mesmo de personal_sign e do hashMessage do ethers
+		{message: "hello", want: "0x50b2c43fd39106bafbba0da34fc430e1f91e3c96ea2acee2bc34119f92b37750"},
+		{message: "", want: "0x5f35dce98ba4fba25530a026ed80b2cecdaa31091ba4958b99b52ea1d068adad"},
+	}
+
+	for _, tt := range tests {
+		if got := MessageHash([]byte(tt.message)); got != common.HexToHash(tt.want) {
+			t.Errorf("MessageHash(%q) = %s, esperado %s", tt.message, got.Hex(), tt.want)
+		}
+	}
+}
+
+func TestTypedDataHash(t *testing.T) {
+	hash, err := TypedDataHash(mailTypedData())
+	if err != nil {
+		t.Fatalf("TypedDataHash: %v", err)
+	}
+	if want := common.HexToHash("0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"); hash != want {
+		t.Errorf("hash %s, esperado %s", hash.Hex(), want.Hex())
+	}
+
+	invalid := mailTypedData()
+	invalid.PrimaryType = "Letter"
+	if _, err := TypedDataHash(invalid); !errors.Is(err, ErrInvalidTypedData) {
+		t.Errorf("esperado ErrInvalidTypedData, recebido %v", err)
+	}
+}
+
+func TestRecoverSigner(t *testing.T) {
+	cow := common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	hash := common.HexToHash("0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2")
+	signature := hexutil.MustDecode(mailSignature)
+
+	withV := func(v byte) []byte {
+		sig := append([]byte(nil), signature...)
+		sig[64] = v
+		return sig
+	}
+
+	tests := []struct {
+		name      string
+		signature []byte
+		want      common.Address
+		wantErr   bool
+	}{
+		{name: "v = 28", signature: signature, want: cow},
+		{name: "v = 1", signature: withV(1), want: cow},
+		{name: "tamanho errado", signature: signature[:64], wantErr: true},
+		{name: "v inválido", signature: withV(29), wantErr: true},
+		{name: "vazia", signature: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := RecoverSigner(hash, tt.signature)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSignature) {
+					t.Fatalf("esperado ErrInvalidSignature, recebido %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RecoverSigner: %v", err)
+			}
+			if signer != tt.want {
+				t.Errorf("signatário %s, esperado %s", signer.Hex(), tt.want.Hex())
+			}
+		})
+	}
+
+	// outro hash recupera outra conta
+	if signer, err := RecoverSigner(MessageHash([]byte("hello")), signature); err == nil && signer == cow {
+		t.Error("a assinatura não deveria valer para outro hash")
+	}
+}
+
+func TestPrivateKeySignerMessages(t *testing.T) {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	if err != nil {
+		t.Fatalf("crypto.ToECDSA: %v", err)
+	}
+	signer, err := NewPrivateKeySigner(key)
+	if err != nil {
+		t.Fatalf("NewPrivateKeySigner: %v", err)
+	}
+	if want := common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"); signer.Address() != want {
+		t.Fatalf("conta %s, esperado %s", signer.Address().Hex(), want.Hex())
+	}
+
+	typedSignature, err := signer.SignTypedData(context.Background(), mailTypedData())
+	if err != nil {
+		t.Fatalf("SignTypedData: %v", err)
+	}
+	// a assinatura secp256k1 é determinística (RFC 6979), então reproduz a do exemplo do EIP-712
+	if got := hexutil.Encode(typedSignature); got != mailSignature {
+		t.Errorf("assinatura %s, esperado %s", got, mailSignature)
+	}
+
+	messageSignature, err := signer.SignMessage(context.Background(), []byte("hello"))
+	if err != nil {
+		t.Fatalf("SignMessage: %v", err)
+	}
+	if v := messageSignature[64]; v != 27 && v != 28 {
+		t.Errorf("v = %d, esperado 27 ou 28", v)
+	}
+	recovered, err := RecoverSigner(MessageHash([]byte("hello")), messageSignature)
+	if err != nil {
+		t.Fatalf("RecoverSigner: %v", err)
+	}
+	if recovered != signer.Address() {
+		t.Errorf("signatário %s, esperado %s", recovered.Hex(), signer.Address().Hex())
+	}
+}
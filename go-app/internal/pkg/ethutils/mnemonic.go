@@ -0,0 +1,133 @@
+package ethutils
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultDerivationPath é o caminho BIP-44 da primeira conta Ethereum (o mesmo do MetaMask e do Ledger Live)
+const DefaultDerivationPath = "m/44'/60'/0'/0/0"
+
+// hardenedKeyStart é o primeiro índice de derivação endurecida (BIP-32)
+const hardenedKeyStart = 0x80000000
+
+// LoadMnemonicSigners lê o mnemônico BIP-39 de mnemonicFile e deriva count contas a partir do caminho BIP-44 informado.
+// passphraseFile é opcional e contém a senha adicional do BIP-39 (a "25ª palavra").
+func LoadMnemonicSigners(mnemonicFile, passphraseFile, path string, count int) ([]*PrivateKeySigner, error) {
+	mnemonic, err := readSecretFile(mnemonicFile)
+	if err != nil {
+		return nil, err
+	}
+	passphrase := ""
+	if passphraseFile != "" {
+		if passphrase, err = readSecretFile(passphraseFile); err != nil {
+			return nil, err
+		}
+	}
+	return NewMnemonicSigners(mnemonic, passphrase, path, count)
+}
+
+// NewMnemonicSigners deriva count chaves de um mnemônico BIP-39: a primeira no caminho BIP-44 informado
+// (padrão DefaultDerivationPath) e as seguintes incrementando o último índice do caminho.
+// O mnemônico e a senha são normalizados em NFKD e as palavras são conferidas contra a lista em inglês do BIP-39
+// e o checksum, para que um mnemônico digitado errado não gere outras contas.
+func NewMnemonicSigners(mnemonic, passphrase, path string, count int) ([]*PrivateKeySigner, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("mnemônico com %d palavras; esperado 12, 15, 18, 21 ou 24", len(words))
+	}
+	mnemonic = strings.Join(words, " ")
+	if _, err := bip39.EntropyFromMnemonic(mnemonic); err != nil {
+		return nil, fmt.Errorf("mnemônico inválido (palavra fora da lista BIP-39 em inglês ou checksum incorreto): %w", err)
+	}
+	if path == "" {
+		path = DefaultDerivationPath
+	}
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("caminho de derivação inválido '%s': %w", path, err)
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("número de contas do mnemônico deve ser positivo")
+	}
+
+	// BIP-39: semente de 64 bytes a partir do mnemônico e da senha
+	seed := bip39.NewSeed(mnemonic, norm.NFKD.String(passphrase))
+
+	signers := make([]*PrivateKeySigner, 0, count)
+	for i := 0; i < count; i++ {
+		accountPath := append(accounts.DerivationPath{}, derivationPath...)
+		accountPath[len(accountPath)-1] += uint32(i)
+
+		key, err := deriveKey(seed, accountPath)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao derivar chave no caminho %s: %w", accountPath, err)
+		}
+		privateKey, err := crypto.ToECDSA(key)
+		if err != nil {
+			return nil, fmt.Errorf("chave derivada inválida: %w", err)
+		}
+		signer, err := NewPrivateKeySigner(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+	return signers, nil
+}
+
+// deriveKey aplica a derivação BIP-32 de chaves privadas secp256k1 a partir da semente
+func deriveKey(seed []byte, path accounts.DerivationPath) ([]byte, error) {
+	curveOrder := crypto.S256().Params().N
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := new(big.Int).SetBytes(sum[:32]), sum[32:]
+	if key.Sign() == 0 || key.Cmp(curveOrder) >= 0 {
+		return nil, fmt.Errorf("semente gera chave mestra inválida")
+	}
+
+	for _, index := range path {
+		keyBytes := key.FillBytes(make([]byte, 32))
+
+		var data []byte
+		if index >= hardenedKeyStart {
+			data = append([]byte{0}, keyBytes...)
+		} else {
+			privateKey, err := crypto.ToECDSA(keyBytes)
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&privateKey.PublicKey)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		tweak := new(big.Int).SetBytes(sum[:32])
+		if tweak.Cmp(curveOrder) >= 0 {
+			return nil, fmt.Errorf("índice %d gera chave inválida", index)
+		}
+		key = tweak.Add(tweak, key)
+		key.Mod(key, curveOrder)
+		if key.Sign() == 0 {
+			return nil, fmt.Errorf("índice %d gera chave inválida", index)
+		}
+		chainCode = sum[32:]
+	}
+	return key.FillBytes(make([]byte, 32)), nil
+}
@@ -0,0 +1,102 @@
+package ethutils
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// testMnemonic é o mnemônico padrão do Hardhat e do Foundry (anvil)
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestNewMnemonicSigners(t *testing.T) {
+	tests := []struct {
+		name       string
+		mnemonic   string
+		passphrase string
+		path       string
+		count      int
+		want       []string
+	}{
+		{
+			name:     "caminho padrão",
+			mnemonic: testMnemonic,
+			count:    3,
+			want: []string{
+				"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+				"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+				"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+			},
+		},
+		{
+			name:     "caminho explícito com espaços extras",
+			mnemonic: "  test test test test test test test test test test test junk\n",
+			path:     "m/44'/60'/0'/0/1",
+			count:    1,
+			want:     []string{"0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signers, err := NewMnemonicSigners(tt.mnemonic, tt.passphrase, tt.path, tt.count)
+			if err != nil {
+				t.Fatalf("NewMnemonicSigners: %v", err)
+			}
+			if len(signers) != len(tt.want) {
+				t.Fatalf("esperado %d contas, recebido %d", len(tt.want), len(signers))
+			}
+			for i, signer := range signers {
+				if want := common.HexToAddress(tt.want[i]); signer.Address() != want {
+					t.Errorf("conta %d: esperado %s, recebido %s", i, want.Hex(), signer.Address().Hex())
+				}
+			}
+		})
+	}
+}
+
+func TestNewMnemonicSignersPassphrase(t *testing.T) {
+	withPassphrase, err := NewMnemonicSigners(testMnemonic, "senha", "", 1)
+	if err != nil {
+		t.Fatalf("NewMnemonicSigners: %v", err)
+	}
+	if withPassphrase[0].Address() == common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266") {
+		t.Error("a senha BIP-39 deve gerar outras contas")
+	}
+
+	// a senha é normalizada em NFKD: "é" composto e decomposto geram a mesma conta
+	composed, err := NewMnemonicSigners(testMnemonic, "caf\u00e9", "", 1)
+	if err != nil {
+		t.Fatalf("NewMnemonicSigners: %v", err)
+	}
+	decomposed, err := NewMnemonicSigners(testMnemonic, "cafe\u0301", "", 1)
+	if err != nil {
+		t.Fatalf("NewMnemonicSigners: %v", err)
+	}
+	if composed[0].Address() != decomposed[0].Address() {
+		t.Errorf("senhas equivalentes em NFKD geraram %s e %s", composed[0].Address().Hex(), decomposed[0].Address().Hex())
+	}
+}
+
+func TestNewMnemonicSignersInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		path     string
+		count    int
+	}{
+		{name: "número de palavras", mnemonic: "test test test test test test test test test test test", count: 1},
+		{name: "palavra fora da lista", mnemonic: "test test test test test test test test test test test junkk", count: 1},
+		{name: "checksum incorreto", mnemonic: "test test test test test test test test test test test test", count: 1},
+		{name: "caminho inválido", mnemonic: testMnemonic, path: "m/44'/x", count: 1},
+		{name: "número de contas", mnemonic: testMnemonic, count: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewMnemonicSigners(tt.mnemonic, "", tt.path, tt.count); err == nil {
+				t.Error("esperado erro")
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package ethutils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// RemoteSignerClient é a conexão com um assinador externo via JSON-RPC (Web3Signer, Clef ou qualquer serviço
+// compatível com eth_signTransaction). As chaves nunca passam pela aplicação.
+type RemoteSignerClient struct {
+	url    string
+	client *rpc.Client
+}
+
+// DialRemoteSigner conecta ao assinador externo em url
+func DialRemoteSigner(ctx context.Context, url string) (*RemoteSignerClient, error) {
+	if url == "" {
+		return nil, fmt.Errorf("URL do assinador externo não informada")
+	}
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao assinador externo %s: %w", url, err)
+	}
+	return &RemoteSignerClient{url: url, client: client}, nil
+}
+
+// Close encerra a conexão com o assinador
+func (c *RemoteSignerClient) Close() {
+	c.client.Close()
+}
+
+// Accounts lista as contas disponíveis no assinador (eth_accounts)
+func (c *RemoteSignerClient) Accounts(ctx context.Context) ([]common.Address, error) {
+	var accounts []common.Address
+	if err := c.client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		return nil, fmt.Errorf("erro ao listar contas do assinador externo %s: %w", c.url, err)
+	}
+	return accounts, nil
+}
+
+// Signer retorna o Signer de uma conta do assinador
+func (c *RemoteSignerClient) Signer(address common.Address) *RemoteSigner {
+	return &RemoteSigner{conn: c, address: address}
+}
+
+// RemoteSigner assina as transações de uma conta no assinador externo
+type RemoteSigner struct {
+	conn    *RemoteSignerClient
+	address common.Address
+}
+
+// Address retorna a conta usada no assinador externo
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx envia os campos da transação ao assinador e confere que a transação devolvida é a pedida,
+// assinada pela conta esperada
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := map[string]interface{}{
+		"from":    s.address,
+		"nonce":   hexutil.Uint64(tx.Nonce()),
+		"gas":     hexutil.Uint64(tx.Gas()),
+		"value":   (*hexutil.Big)(tx.Value()),
+		"data":    hexutil.Bytes(tx.Data()),
+		"chainId": (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		args["to"] = tx.To()
+	}
+	if tx.Type() == types.LegacyTxType {
+		args["gasPrice"] = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		args["maxFeePerGas"] = (*hexutil.Big)(tx.GasFeeCap())
+		args["maxPriorityFeePerGas"] = (*hexutil.Big)(tx.GasTipCap())
+	}
+
+	var result json.RawMessage
+	if err := s.conn.client.CallContext(ctx, &result, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("erro no assinador externo %s: %w", s.conn.url, err)
+	}
+
+	// Web3Signer responde a transação codificada; Clef responde um objeto com o campo "raw"
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err != nil {
+		var response struct {
+			Raw hexutil.Bytes `json:"raw"`
+		}
+		if err := json.Unmarshal(result, &response); err != nil || len(response.Raw) == 0 {
+			return nil, fmt.Errorf("resposta inesperada do assinador externo %s: %s", s.conn.url, result)
+		}
+		raw = response.Raw
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar transação assinada pelo assinador externo: %w", err)
+	}
+	if err := checkSignedTx(tx, signed, chainID, s.address); err != nil {
+		return nil, fmt.Errorf("assinador externo %s: %w", s.conn.url, err)
+	}
+	return signed, nil
+}
+
+// SignMessage pede ao assinador a assinatura EIP-191 da mensagem (eth_sign) e confere que ela é da conta esperada
+func (s *RemoteSigner) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	if err := s.conn.client.CallContext(ctx, &signature, "eth_sign", s.address, hexutil.Bytes(message)); err != nil {
+		return nil, fmt.Errorf("erro no assinador externo %s: %w", s.conn.url, err)
+	}
+	if err := checkSignature(MessageHash(message), signature, s.address); err != nil {
+		return nil, fmt.Errorf("assinador externo %s: %w", s.conn.url, err)
+	}
+	return signature, nil
+}
+
+// SignTypedData pede ao assinador a assinatura EIP-712 dos dados tipados (eth_signTypedData, como no Web3Signer)
+// e confere que ela é da conta esperada
+func (s *RemoteSigner) SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	var signature hexutil.Bytes
+	if err := s.conn.client.CallContext(ctx, &signature, "eth_signTypedData", s.address, typedData); err != nil {
+		return nil, fmt.Errorf("erro no assinador externo %s: %w", s.conn.url, err)
+	}
+	if err := checkSignature(hash, signature, s.address); err != nil {
+		return nil, fmt.Errorf("assinador externo %s: %w", s.conn.url, err)
+	}
+	return signature, nil
+}
+
+// checkSignedTx confere que a transação assinada tem os mesmos campos da pedida, incluindo tipo, taxas e chain ID,
+// e foi assinada por from
+func checkSignedTx(want, signed *types.Transaction, chainID *big.Int, from common.Address) error {
+	sameTo := (want.To() == nil && signed.To() == nil) ||
+		(want.To() != nil && signed.To() != nil && *want.To() == *signed.To())
+	if signed.Nonce() != want.Nonce() || signed.Gas() != want.Gas() || !sameTo ||
+		signed.Value().Cmp(want.Value()) != 0 || !bytes.Equal(signed.Data(), want.Data()) {
+		return fmt.Errorf("transação assinada difere da transação pedida")
+	}
+	if signed.Type() != want.Type() {
+		return fmt.Errorf("transação assinada com tipo %d, esperado %d", signed.Type(), want.Type())
+	}
+	if signed.GasPrice().Cmp(want.GasPrice()) != 0 || signed.GasFeeCap().Cmp(want.GasFeeCap()) != 0 ||
+		signed.GasTipCap().Cmp(want.GasTipCap()) != 0 {
+		return fmt.Errorf("taxas da transação assinada diferem das pedidas")
+	}
+	// uma transação legada sem EIP-155 tem chain ID zero e é recusada aqui
+	if signed.ChainId().Cmp(chainID) != 0 {
+		return fmt.Errorf("transação assinada para a rede %s, esperado %s", signed.ChainId(), chainID)
+	}
+
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return fmt.Errorf("erro ao recuperar remetente da transação assinada: %w", err)
+	}
+	if sender != from {
+		return fmt.Errorf("transação assinada por %s, esperado %s", sender.Hex(), from.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package ethutils
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newSignerStub sobe um servidor JSON-RPC que responde eth_signTransaction com result
+func newSignerStub(t *testing.T, result interface{}) *RemoteSignerClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("requisição inválida: %v", err)
+			return
+		}
+		if req.Method != "eth_signTransaction" {
+			t.Errorf("método inesperado %s", req.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := DialRemoteSigner(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("DialRemoteSigner: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func signTestTx(t *testing.T, txData types.TxData, chainID *big.Int, key *ecdsa.PrivateKey) hexutil.Bytes {
+	t.Helper()
+	signer := types.LatestSignerForChainID(chainID)
+	if chainID == nil {
+		signer = types.HomesteadSigner{}
+	}
+	signed, err := types.SignNewTx(key, signer, txData)
+	if err != nil {
+		t.Fatalf("SignNewTx: %v", err)
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	return raw
+}
+
+func TestRemoteSignerSignTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(1337)
+	to := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+
+	dynamicTx := func(feeCap int64) *types.DynamicFeeTx {
+		return &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     7,
+			GasTipCap: big.NewInt(1_000_000_000),
+			GasFeeCap: big.NewInt(feeCap),
+			Gas:       50_000,
+			To:        &to,
+			Value:     big.NewInt(0),
+			Data:      []byte{0x60, 0xfe, 0x47, 0xb1},
+		}
+	}
+	legacyTx := &types.LegacyTx{
+		Nonce:    7,
+		GasPrice: big.NewInt(2_000_000_000),
+		Gas:      50_000,
+		To:       &to,
+		Value:    big.NewInt(0),
+		Data:     []byte{0x60, 0xfe, 0x47, 0xb1},
+	}
+	otherChainTx := dynamicTx(2_000_000_000)
+	otherChainTx.ChainID = big.NewInt(1)
+
+	tests := []struct {
+		name    string
+		want    types.TxData
+		result  interface{}
+		wantErr bool
+	}{
+		{
+			name:   "transação assinada (Web3Signer)",
+			want:   dynamicTx(2_000_000_000),
+			result: signTestTx(t, dynamicTx(2_000_000_000), chainID, key),
+		},
+		{
+			name:   "objeto com raw (Clef)",
+			want:   dynamicTx(2_000_000_000),
+			result: map[string]interface{}{"raw": signTestTx(t, dynamicTx(2_000_000_000), chainID, key)},
+		},
+		{
+			name:   "transação legada",
+			want:   legacyTx,
+			result: signTestTx(t, legacyTx, chainID, key),
+		},
+		{
+			name:    "taxa máxima alterada",
+			want:    dynamicTx(2_000_000_000),
+			result:  signTestTx(t, dynamicTx(90_000_000_000), chainID, key),
+			wantErr: true,
+		},
+		{
+			name:    "tipo alterado",
+			want:    dynamicTx(2_000_000_000),
+			result:  signTestTx(t, legacyTx, chainID, key),
+			wantErr: true,
+		},
+		{
+			name:    "outra rede",
+			want:    dynamicTx(2_000_000_000),
+			result:  signTestTx(t, otherChainTx, otherChainTx.ChainID, key),
+			wantErr: true,
+		},
+		{
+			name:    "legada sem EIP-155",
+			want:    legacyTx,
+			result:  signTestTx(t, legacyTx, nil, key),
+			wantErr: true,
+		},
+		{
+			name:    "outra conta",
+			want:    dynamicTx(2_000_000_000),
+			result:  signTestTx(t, dynamicTx(2_000_000_000), chainID, otherKey),
+			wantErr: true,
+		},
+		{
+			name:    "resposta inesperada",
+			want:    dynamicTx(2_000_000_000),
+			result:  map[string]interface{}{"tx": "0x"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := newSignerStub(t, tt.result).Signer(from)
+			want := types.NewTx(tt.want)
+
+			signed, err := signer.SignTx(context.Background(), want, chainID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("esperado erro")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SignTx: %v", err)
+			}
+			if sender, _ := types.Sender(types.LatestSignerForChainID(chainID), signed); sender != from {
+				t.Errorf("remetente %s, esperado %s", sender.Hex(), from.Hex())
+			}
+		})
+	}
+}
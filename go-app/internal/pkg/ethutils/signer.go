@@ -0,0 +1,79 @@
+package ethutils
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer assina transações e mensagens de uma conta sem expor onde a chave está guardada
+// (chave em memória, keystore cifrado, mnemônico ou assinador externo)
+type Signer interface {
+	// Address retorna a conta que assina as transações
+	Address() common.Address
+	// SignTx assina a transação para a rede chainID e retorna a transação assinada
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignMessage assina uma mensagem pelo EIP-191 (personal_sign) e retorna a assinatura r || s || v, com v 27/28
+	SignMessage(ctx context.Context, message []byte) ([]byte, error)
+	// SignTypedData assina dados tipados pelo EIP-712 e retorna a assinatura r || s || v, com v 27/28
+	SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error)
+}
+
+// PrivateKeySigner assina com uma chave privada mantida em memória
+type PrivateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewPrivateKeySigner cria um Signer a partir de uma chave privada
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) (*PrivateKeySigner, error) {
+	if key == nil {
+		return nil, fmt.Errorf("chave privada não pode ser nula")
+	}
+	address, err := GetPublicKeyAddress(key)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKeySigner{key: key, address: address}, nil
+}
+
+// Address retorna o endereço da chave
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx assina a transação com o signer mais recente da rede chainID (EIP-155 e tipos posteriores)
+func (s *PrivateKeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao assinar transação com a chave de %s: %w", s.address.Hex(), err)
+	}
+	return signed, nil
+}
+
+// SignMessage assina o hash EIP-191 da mensagem com a chave
+func (s *PrivateKeySigner) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	signature, err := signHash(MessageHash(message), s.key)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao assinar mensagem com a chave de %s: %w", s.address.Hex(), err)
+	}
+	return signature, nil
+}
+
+// SignTypedData assina o hash EIP-712 dos dados tipados com a chave
+func (s *PrivateKeySigner) SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signHash(hash, s.key)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao assinar dados tipados com a chave de %s: %w", s.address.Hex(), err)
+	}
+	return signature, nil
+}
@@ -0,0 +1,49 @@
+package ethutils
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// MaxUint256 é o maior valor representável por um uint256 (2^256 - 1)
+var MaxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// ParseUint256 converte uma string decimal ou hexadecimal com prefixo 0x em um valor dentro do intervalo de um uint256
+func ParseUint256(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("valor vazio")
+	}
+
+	base, digits := 10, s
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		base, digits = 16, s[2:]
+	}
+	if digits == "" || strings.ContainsAny(digits, "+-_") {
+		return nil, fmt.Errorf("valor '%s' não é um número válido", s)
+	}
+
+	value, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, fmt.Errorf("valor '%s' não é um número válido", s)
+	}
+	if err := ValidateUint256(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// ValidateUint256 verifica se o valor está entre 0 e 2^256 - 1
+func ValidateUint256(value *big.Int) error {
+	if value == nil {
+		return fmt.Errorf("valor não pode ser nulo")
+	}
+	if value.Sign() < 0 {
+		return fmt.Errorf("o valor não pode ser negativo")
+	}
+	if value.Cmp(MaxUint256) > 0 {
+		return fmt.Errorf("o valor excede o máximo de um uint256")
+	}
+	return nil
+}
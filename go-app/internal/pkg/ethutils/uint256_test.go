@@ -0,0 +1,75 @@
+package ethutils
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestParseUint256(t *testing.T) {
+	const max = "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "zero", input: "0", want: "0"},
+		{name: "decimal", input: "42", want: "42"},
+		{name: "espaços nas pontas", input: " 42\n", want: "42"},
+		{name: "hexadecimal", input: "0x2a", want: "42"},
+		{name: "hexadecimal com 0X", input: "0X2A", want: "42"},
+		{name: "máximo do uint256", input: max, want: max},
+		{name: "máximo em hexadecimal", input: "0x" + strings.Repeat("f", 64), want: max},
+		{name: "acima do máximo", input: "115792089237316195423570985008687907853269984665640564039457584007913129639936", wantErr: true},
+		{name: "hexadecimal acima do máximo", input: "0x1" + strings.Repeat("0", 64), wantErr: true},
+		{name: "negativo", input: "-1", wantErr: true},
+		{name: "sinal positivo", input: "+1", wantErr: true},
+		{name: "separador", input: "1_000", wantErr: true},
+		{name: "vazio", input: "", wantErr: true},
+		{name: "prefixo sem dígitos", input: "0x", wantErr: true},
+		{name: "texto", input: "dez", wantErr: true},
+		{name: "decimal com ponto", input: "1.5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := ParseUint256(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("esperado erro, recebido %s", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseUint256: %v", err)
+			}
+			if value.String() != tt.want {
+				t.Errorf("valor %s, esperado %s", value, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateUint256(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   *big.Int
+		wantErr bool
+	}{
+		{name: "zero", value: big.NewInt(0)},
+		{name: "máximo", value: MaxUint256},
+		{name: "nulo", value: nil, wantErr: true},
+		{name: "negativo", value: big.NewInt(-1), wantErr: true},
+		{name: "acima do máximo", value: new(big.Int).Add(MaxUint256, big.NewInt(1)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateUint256(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUint256(%v) = %v, esperado erro: %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
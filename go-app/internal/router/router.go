@@ -15,10 +15,38 @@ func NewRouter(c *handler.Handler) http.Handler {
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
+	// Rotas sem nome de contrato atendem o contrato padrão (DEFAULT_CONTRACT)
 	r.Get("/value", c.GetValueHandler)
-	r.Post("/value", c.SetValueHandler)
+	r.With(c.Idempotent).Post("/value", c.SetValueHandler)
 	r.Post("/sync", c.SyncValueHandler)
+	r.Get("/sync/status", c.SyncStatusHandler)
 	r.Get("/check", c.CheckValueHandler)
+	r.Get("/history", c.GetHistoryHandler)
+	r.Post("/tx/raw", c.SendRawTransactionHandler)
+	r.Get("/tx/{hash}", c.GetTransactionHandler)
+	r.Post("/tx/{hash}/speedup", c.SpeedUpTransactionHandler)
+	r.Post("/tx/{hash}/cancel", c.CancelTransactionHandler)
+
+	// Assinatura e verificação de mensagens (EIP-191) e dados tipados (EIP-712) com as contas do pool.
+	// Assinar exige o token de SIGNING_API_TOKEN; verificar é público.
+	r.With(c.SigningAuth).Post("/sign/message", c.SignMessageHandler)
+	r.With(c.SigningAuth).Post("/sign/typed-data", c.SignTypedDataHandler)
+	r.Post("/verify/message", c.VerifyMessageHandler)
+	r.Post("/verify/typed-data", c.VerifyTypedDataHandler)
+
+	r.Get("/contracts", c.ListContractsHandler)
+	r.Route("/contracts/{name}", func(r chi.Router) {
+		r.Get("/value", c.GetValueHandler)
+		r.With(c.Idempotent).Post("/value", c.SetValueHandler)
+		r.Post("/sync", c.SyncValueHandler)
+		r.Get("/sync/status", c.SyncStatusHandler)
+		r.Get("/check", c.CheckValueHandler)
+		r.Get("/history", c.GetHistoryHandler)
+		r.Get("/methods", c.ListMethodsHandler)
+		r.Post("/call/{method}", c.CallMethodHandler)
+		r.Post("/transact/{method}", c.TransactMethodHandler)
+		r.Post("/build/{method}", c.BuildMethodHandler)
+	})
 
 	return r
 }
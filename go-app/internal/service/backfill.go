@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
+	"github.com/vmm2136/besu_challenge/go-app/internal/database"
+)
+
+// BackfillReport resume uma execução do backfill
+type BackfillReport struct {
+	FromBlock uint64 // primeiro bloco lido nesta execução (após o checkpoint)
+	ToBlock   uint64 // último bloco do intervalo
+	Resumed   bool   // se a execução continuou de um checkpoint anterior
+	Batches   int
+	Events    int
+}
+
+// Backfiller reconstrói o histórico de um contrato a partir dos eventos ValueChanged já emitidos na rede.
+// Os lotes de blocos são lidos em paralelo; o checkpoint só avança sobre lotes contíguos já gravados,
+// então uma execução interrompida pode ser retomada sem lacunas. Regravar um lote não duplica registros.
+// Cada bloco inicial tem o seu checkpoint, que cobre os blocos dele até o checkpoint; só uma execução com o
+// mesmo bloco inicial o retoma.
+type Backfiller struct {
+	contractClient    contract.ContractClient
+	dbClient          database.DBClient
+	indexerStartBlock uint64
+	batchSize         uint64
+	workers           int
+}
+
+// NewBackfiller cria um Backfiller que lê lotes de batchSize blocos com workers leituras simultâneas.
+// indexerStartBlock é o bloco em que o indexador de eventos começa quando ainda não tem cursor salvo.
+func NewBackfiller(client contract.ContractClient, dbClient database.DBClient, indexerStartBlock, batchSize uint64, workers int) (*Backfiller, error) {
+	if client == nil {
+		return nil, fmt.Errorf("cliente do contrato não pode ser nulo")
+	}
+	if dbClient == nil {
+		return nil, fmt.Errorf("cliente de banco de dados não pode ser nulo")
+	}
+	if !client.SupportsValueChanged() {
+		return nil, fmt.Errorf("contrato %s não declara o evento '%s'", client.Name(), contract.ValueChangedEventName)
+	}
+	if batchSize == 0 {
+		return nil, fmt.Errorf("tamanho do lote do backfill deve ser positivo")
+	}
+	if workers <= 0 {
+		return nil, fmt.Errorf("número de workers do backfill deve ser positivo")
+	}
+	return &Backfiller{
+		contractClient:    client,
+		dbClient:          dbClient,
+		indexerStartBlock: indexerStartBlock,
+		batchSize:         batchSize,
+		workers:           workers,
+	}, nil
+}
+
+// backfillBatch é um intervalo de blocos (inclusive) lido por um worker
+type backfillBatch struct {
+	index     int
+	fromBlock uint64
+	toBlock   uint64
+}
+
+type backfillResult struct {
+	batch  backfillBatch
+	events int
+	err    error
+}
+
+// Run preenche o histórico entre fromBlock e toBlock, continuando do checkpoint de uma execução anterior com o
+// mesmo fromBlock. Ao terminar, o cursor do indexador de eventos é avançado até toBlock se o intervalo começar
+// no cursor ou antes dele, para que o indexador não releia o mesmo intervalo nem pule os blocos anteriores.
+func (b *Backfiller) Run(ctx context.Context, fromBlock, toBlock uint64) (BackfillReport, error) {
+	report := BackfillReport{FromBlock: fromBlock, ToBlock: toBlock}
+	if fromBlock > toBlock {
+		return report, fmt.Errorf("bloco inicial %d maior que o final %d", fromBlock, toBlock)
+	}
+
+	cursorName := backfillCursorName(b.contractClient.Address(), fromBlock)
+	checkpoint, found, err := b.dbClient.GetIndexerCursor(ctx, cursorName)
+	if err != nil {
+		return report, err
+	}
+	if found && checkpoint >= fromBlock {
+		if checkpoint >= toBlock {
+			report.FromBlock = toBlock + 1
+			report.Resumed = true
+			return report, b.advanceIndexerCursor(ctx, fromBlock, toBlock)
+		}
+		report.FromBlock = checkpoint + 1
+		report.Resumed = true
+	}
+
+	var batches []backfillBatch
+	for start := report.FromBlock; start <= toBlock; start += b.batchSize {
+		end := start + b.batchSize - 1
+		if end > toBlock || end < start {
+			end = toBlock
+		}
+		batches = append(batches, backfillBatch{index: len(batches), fromBlock: start, toBlock: end})
+		if end == toBlock {
+			break
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pending := make(chan backfillBatch)
+	results := make(chan backfillResult)
+	var wg sync.WaitGroup
+	for w := 0; w < b.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range pending {
+				events, err := b.fillBatch(ctx, batch)
+				results <- backfillResult{batch: batch, events: events, err: err}
+			}
+		}()
+	}
+	go func() {
+		defer close(pending)
+		for _, batch := range batches {
+			select {
+			case pending <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// done marca os lotes gravados; next é o primeiro lote ainda não coberto pelo checkpoint
+	done := make([]bool, len(batches))
+	next := 0
+	var runErr error
+	for result := range results {
+		if result.err != nil {
+			if runErr == nil {
+				runErr = fmt.Errorf("erro no lote de blocos %d a %d: %w", result.batch.fromBlock, result.batch.toBlock, result.err)
+				cancel()
+			}
+			continue
+		}
+		done[result.batch.index] = true
+		report.Batches++
+		report.Events += result.events
+
+		advanced := next
+		for advanced < len(batches) && done[advanced] {
+			advanced++
+		}
+		if advanced == next || runErr != nil {
+			continue
+		}
+		next = advanced
+		if err := b.dbClient.SaveValueChanges(ctx, cursorName, batches[next-1].toBlock, nil); err != nil && runErr == nil {
+			runErr = err
+			cancel()
+		}
+	}
+	if runErr != nil {
+		return report, runErr
+	}
+
+	return report, b.advanceIndexerCursor(ctx, fromBlock, toBlock)
+}
+
+// fillBatch lê os eventos de um lote e os grava no histórico
+func (b *Backfiller) fillBatch(ctx context.Context, batch backfillBatch) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	events, err := b.contractClient.FilterValueChanged(ctx, batch.fromBlock, batch.toBlock)
+	if err != nil {
+		return 0, err
+	}
+
+	records := make([]database.ValueChangeRecord, 0, len(events))
+	for _, event := range events {
+		records = append(records, valueChangeRecordFromEvent(event, database.HistorySourceEvent))
+	}
+	if err := b.dbClient.InsertValueChanges(ctx, records); err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// advanceIndexerCursor leva o cursor do indexador de eventos até toBlock depois de preenchidos os blocos de
+// fromBlock a toBlock. O cursor só avança se o próximo bloco que o indexador leria estiver nesse intervalo;
+// com fromBlock depois dele, os blocos entre os dois ainda não foram lidos e o cursor fica onde está.
+func (b *Backfiller) advanceIndexerCursor(ctx context.Context, fromBlock, toBlock uint64) error {
+	indexerCursor := indexerCursorName(b.contractClient.Address())
+	cursor, found, err := b.dbClient.GetIndexerCursor(ctx, indexerCursor)
+	if err != nil {
+		return err
+	}
+	next := b.indexerStartBlock
+	if found {
+		next = cursor + 1
+	}
+	if next > toBlock || fromBlock > next {
+		return nil
+	}
+	return b.dbClient.SaveValueChanges(ctx, indexerCursor, toBlock, nil)
+}
+
+// backfillCursorName retorna o nome do checkpoint do backfill de um contrato iniciado em fromBlock
+func backfillCursorName(address common.Address, fromBlock uint64) string {
+	return fmt.Sprintf("backfill:value_changed:%s:%d", strings.ToLower(address.Hex()), fromBlock)
+}
@@ -2,100 +2,378 @@ package service
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
 	"github.com/vmm2136/besu_challenge/go-app/internal/database"
 	"github.com/vmm2136/besu_challenge/go-app/internal/pkg/ethutils"
 )
 
-// Constante que será  usada no banco como chave do contrato
-const SimpleStorageValueKey = "simple_storage_current_value"
+// ContractValueKey retorna a chave usada no banco para o valor atual de um contrato.
+// Para o SimpleStorage ela é "simple_storage_current_value", a mesma usada antes do registro de contratos.
+func ContractValueKey(contractName string) string {
+	runes := []rune(contractName)
+	var key strings.Builder
+	for i, r := range runes {
+		switch {
+		case r == '.' || r == '-':
+			key.WriteByte('_')
+		case unicode.IsUpper(r):
+			// separa palavras em "SimpleStorage" e "ERC20Token", sem quebrar siglas
+			if i > 0 && runes[i-1] != '.' && runes[i-1] != '-' &&
+				(!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				key.WriteByte('_')
+			}
+			key.WriteRune(unicode.ToLower(r))
+		default:
+			key.WriteRune(r)
+		}
+	}
+	return key.String() + "_current_value"
+}
 
 // ContractService define a interface para a lógica do contrato
 type ContractService interface {
-	GetCurrentValue(ctx context.Context) (*big.Int, common.Address, error)
-	SetNewValue(ctx context.Context, value int64) (common.Hash, error)
-	SyncContractValue(ctx context.Context) (networkValue *big.Int, dbValue *big.Int, err error)
+	ContractName() string
+	ContractAddress() common.Address
+	SupportsValue() bool
+	GetCurrentValue(ctx context.Context, ref contract.BlockRef) (*big.Int, contract.BlockInfo, error)
+	Transactors(ctx context.Context) []contract.TransactorInfo
+	SetNewValue(ctx context.Context, value *big.Int) (common.Hash, error)
+	SyncContractValue(ctx context.Context) (networkValue *big.Int, dbValue *big.Int, block uint64, err error)
 	CheckContractValue(ctx context.Context) (bool, *big.Int, *big.Int, error)
+	GetLatestBlockNumber(ctx context.Context) (uint64, error)
+	NewHeads() (<-chan *types.Header, func())
+	GetValueHistory(ctx context.Context, filter database.HistoryFilter) ([]database.HistoryEntry, int, error)
+	GetTransactionStatus(ctx context.Context, hash common.Hash) (*contract.TxStatus, error)
+	WaitForTransaction(ctx context.Context, hash common.Hash) (*contract.TxStatus, error)
+	SpeedUpTransaction(ctx context.Context, hash common.Hash) (common.Hash, error)
+	CancelTransaction(ctx context.Context, hash common.Hash) (common.Hash, error)
+	ListMethods() []contract.MethodInfo
+	CallMethod(ctx context.Context, method string, args []json.RawMessage) ([]contract.MethodOutput, error)
+	TransactMethod(ctx context.Context, method string, args []json.RawMessage, value *big.Int) (common.Hash, error)
+	BuildTransaction(ctx context.Context, method string, args []json.RawMessage, value *big.Int, from common.Address) (*contract.BuiltTransaction, error)
+	SendRawTransaction(ctx context.Context, tx *types.Transaction, allowed func(method string) bool) (contract.RawTransaction, error)
 }
 
 // contractServiceImpl implementa ContractService
 type contractServiceImpl struct {
-	contractClient contract.ContractClient
-	dbClient       database.DBClient
-	privateKey     *ecdsa.PrivateKey
+	contractClient    contract.ContractClient
+	dbClient          database.DBClient
+	transactors       *contract.TransactorPool
+	syncConfirmations uint64
+	valueKey          string
 }
 
-// NewContractService cria uma nova instância de ContractService
-func NewContractService(client contract.ContractClient, dbClient database.DBClient, privateKey *ecdsa.PrivateKey) (ContractService, error) {
-	if privateKey == nil {
-		return nil, fmt.Errorf("chave privada para o serviço não pode ser nula")
+// NewContractService cria uma nova instância de ContractService.
+// syncConfirmations é quantos blocos abaixo do último a sincronização lê o valor do contrato.
+func NewContractService(client contract.ContractClient, dbClient database.DBClient, transactors *contract.TransactorPool, syncConfirmations uint64) (ContractService, error) {
+	if transactors == nil {
+		return nil, fmt.Errorf("pool de transatores para o serviço não pode ser nulo")
 	}
 	if dbClient == nil {
 		return nil, fmt.Errorf("cliente de banco de dados não pode ser nulo")
 	}
+	if client == nil {
+		return nil, fmt.Errorf("cliente do contrato não pode ser nulo")
+	}
 	return &contractServiceImpl{
-		contractClient: client,
-		dbClient:       dbClient,
-		privateKey:     privateKey,
+		contractClient:    client,
+		dbClient:          dbClient,
+		transactors:       transactors,
+		syncConfirmations: syncConfirmations,
+		valueKey:          ContractValueKey(client.Name()),
 	}, nil
 }
 
-// GetCurrentValue obtém o valor atual do contrato e o endereço do transator
-func (s *contractServiceImpl) GetCurrentValue(ctx context.Context) (*big.Int, common.Address, error) {
-	value, err := s.contractClient.GetValue(ctx) // Obtém da rede
-	if err != nil {
-		return nil, common.Address{}, fmt.Errorf("erro ao obter valor do contrato: %w", err)
-	}
+// ContractName retorna o nome do contrato atendido pelo serviço
+func (s *contractServiceImpl) ContractName() string {
+	return s.contractClient.Name()
+}
 
-	transactorAddress, err := ethutils.GetPublicKeyAddress(s.privateKey)
+// ContractAddress retorna o endereço do contrato atendido pelo serviço
+func (s *contractServiceImpl) ContractAddress() common.Address {
+	return s.contractClient.Address()
+}
+
+// SupportsValue indica se o contrato possui as funções 'get' e 'set'
+func (s *contractServiceImpl) SupportsValue() bool {
+	return s.contractClient.SupportsValue()
+}
+
+// GetCurrentValue obtém o valor do contrato no bloco referenciado (por padrão, o último) e o bloco em que a leitura
+// foi feita
+func (s *contractServiceImpl) GetCurrentValue(ctx context.Context, ref contract.BlockRef) (*big.Int, contract.BlockInfo, error) {
+	value, block, err := s.contractClient.GetValueAtBlock(ctx, ref) // Obtém da rede
 	if err != nil {
-		return nil, common.Address{}, fmt.Errorf("erro ao obter endereço público do transator: %w", err)
+		return nil, contract.BlockInfo{}, fmt.Errorf("erro ao obter valor do contrato: %w", err)
 	}
+	return value, block, nil
+}
 
-	return value, transactorAddress, nil
+// Transactors descreve as contas do pool que assinam as escritas (saldo e transações pendentes)
+func (s *contractServiceImpl) Transactors(ctx context.Context) []contract.TransactorInfo {
+	return s.transactors.Accounts(ctx)
 }
 
 // SetNewValue define um novo valor no contrato
-func (s *contractServiceImpl) SetNewValue(ctx context.Context, value int64) (common.Hash, error) {
-	txHash, err := s.contractClient.SetValue(ctx, big.NewInt(value), s.privateKey)
+func (s *contractServiceImpl) SetNewValue(ctx context.Context, value *big.Int) (common.Hash, error) {
+	if err := ethutils.ValidateUint256(value); err != nil {
+		return common.Hash{}, fmt.Errorf("valor inválido para o contrato: %w", err)
+	}
+
+	txHash, err := s.contractClient.SetValue(ctx, value, s.transactors)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("erro ao definir novo valor no contrato: %w", err)
 	}
 
+	go s.recordConfirmedWrite(txHash, new(big.Int).Set(value))
+
 	return txHash, nil
 }
 
-// SyncContractValue busca o valor do contrato na rede e o sincroniza com o banco de dados
-func (s *contractServiceImpl) SyncContractValue(ctx context.Context) (*big.Int, *big.Int, error) {
-	networkValue, err := s.contractClient.GetValue(ctx)
+// SpeedUpTransaction reenvia uma transação pendente com taxas maiores, assinada pela mesma conta do pool
+func (s *contractServiceImpl) SpeedUpTransaction(ctx context.Context, hash common.Hash) (common.Hash, error) {
+	signer, err := s.replacementSigner(ctx, hash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.contractClient.SpeedUpTransaction(ctx, hash, signer)
+}
+
+// CancelTransaction substitui uma transação pendente por uma transferência vazia com o mesmo nonce
+func (s *contractServiceImpl) CancelTransaction(ctx context.Context, hash common.Hash) (common.Hash, error) {
+	signer, err := s.replacementSigner(ctx, hash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.contractClient.CancelTransaction(ctx, hash, signer)
+}
+
+// replacementSigner retorna o assinador da conta que enviou a transação; a substituta precisa usar o mesmo nonce
+func (s *contractServiceImpl) replacementSigner(ctx context.Context, hash common.Hash) (ethutils.Signer, error) {
+	status, err := s.contractClient.TransactionStatus(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := s.transactors.SignerFor(status.From)
+	if !ok {
+		return nil, fmt.Errorf("%w: enviada por %s, que não está no pool de transatores", contract.ErrTxNotReplaceable, status.From.Hex())
+	}
+	return signer, nil
+}
+
+// ListMethods lista as funções do ABI do contrato
+func (s *contractServiceImpl) ListMethods() []contract.MethodInfo {
+	return s.contractClient.Methods()
+}
+
+// CallMethod executa uma função somente leitura do contrato
+func (s *contractServiceImpl) CallMethod(ctx context.Context, method string, args []json.RawMessage) ([]contract.MethodOutput, error) {
+	return s.contractClient.CallMethod(ctx, method, args)
+}
+
+// TransactMethod envia uma transação para uma função do contrato assinada por uma conta do pool de transatores
+func (s *contractServiceImpl) TransactMethod(ctx context.Context, method string, args []json.RawMessage, value *big.Int) (common.Hash, error) {
+	return s.contractClient.TransactMethod(ctx, method, args, value, s.transactors)
+}
+
+// BuildTransaction monta uma transação não assinada de from para a função method, para ser assinada pela carteira do cliente.
+// Contas do pool de transatores são recusadas (ErrManagedSender): a transação não seria aceita em POST /tx/raw.
+func (s *contractServiceImpl) BuildTransaction(ctx context.Context, method string, args []json.RawMessage, value *big.Int, from common.Address) (*contract.BuiltTransaction, error) {
+	if _, managed := s.transactors.SignerFor(from); managed {
+		return nil, fmt.Errorf("%w: %s", ErrManagedSender, from.Hex())
+	}
+	return s.contractClient.BuildTransaction(ctx, method, args, value, from)
+}
+
+// SendRawTransaction envia uma transação assinada pelo cliente para o contrato, sem usar as contas do pool
+func (s *contractServiceImpl) SendRawTransaction(ctx context.Context, tx *types.Transaction, allowed func(method string) bool) (contract.RawTransaction, error) {
+	return s.contractClient.SendRawTransaction(ctx, tx, allowed)
+}
+
+// recordConfirmedWrite aguarda a confirmação de uma escrita feita pela API e a acrescenta ao histórico
+func (s *contractServiceImpl) recordConfirmedWrite(txHash common.Hash, value *big.Int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
+
+	status, err := s.contractClient.WaitForConfirmation(ctx, txHash)
+	if err != nil {
+		fmt.Printf("Erro ao aguardar confirmação da transação %s para o histórico: %v\n", txHash.Hex(), err)
+		return
+	}
+	if status.State != contract.TxStateConfirmed {
+		return
+	}
+
+	events, err := s.contractClient.TransactionValueChanges(ctx, txHash)
+	if err != nil {
+		fmt.Printf("Erro ao ler eventos da transação %s para o histórico: %v\n", txHash.Hex(), err)
+		return
+	}
+
+	records := make([]database.ValueChangeRecord, 0, len(events))
+	for _, event := range events {
+		records = append(records, valueChangeRecordFromEvent(event, database.HistorySourceWrite))
+	}
+	if len(records) == 0 {
+		// contrato sem o evento ValueChanged: registra apenas o valor escrito
+		records = append(records, database.ValueChangeRecord{
+			ContractAddress: s.contractAddressKey(),
+			Source:          database.HistorySourceWrite,
+			BlockNumber:     status.BlockNumber,
+			BlockHash:       status.BlockHash.Hex(),
+			TxHash:          txHash.Hex(),
+			Setter:          strings.ToLower(status.From.Hex()),
+			NewValue:        value,
+		})
+	}
+
+	for _, record := range records {
+		if err := s.dbClient.AppendValueHistory(ctx, record); err != nil {
+			fmt.Printf("Erro ao gravar escrita confirmada %s no histórico: %v\n", txHash.Hex(), err)
+			return
+		}
+	}
+}
+
+// SyncContractValue lê o valor do contrato no bloco latest - N confirmações, o sincroniza com o banco de dados e
+// retorna também o bloco lido. Antes de gravar, verifica se o bloco da última sincronização ainda é canônico; se não for, desfaz o histórico até o ponto de fork.
+func (s *contractServiceImpl) SyncContractValue(ctx context.Context) (*big.Int, *big.Int, uint64, error) {
+	head, err := s.contractClient.LatestBlockNumber(ctx)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("erro ao obter último bloco para sincronização: %w", err)
+	}
+	var target uint64
+	if head > s.syncConfirmations {
+		target = head - s.syncConfirmations
+	}
+
+	stored, err := s.dbClient.GetSyncedValue(ctx, s.valueKey)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("erro ao obter valor do DB para sincronização (chave %s): %w", s.valueKey, err)
+	}
+
+	dbValue := big.NewInt(0)
+	if stored != nil {
+		dbValue = stored.Value
+		if err := s.checkReorg(ctx, stored, head); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	networkValue, err := s.contractClient.GetValueAt(ctx, target)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("erro ao obter valor da rede no bloco %d para sincronização: %w", target, err)
+	}
+	targetHash, err := s.contractClient.BlockHashAt(ctx, target)
 	if err != nil {
-		return nil, nil, fmt.Errorf("erro ao obter valor da rede para sincronização: %w", err)
+		return nil, nil, 0, fmt.Errorf("erro ao obter hash do bloco %d para sincronização: %w", target, err)
 	}
 
-	dbValue, err := s.dbClient.GetContractValue(ctx, SimpleStorageValueKey)
+	if stored != nil && stored.BlockNumber == target && stored.BlockHash == targetHash.Hex() && networkValue.Cmp(dbValue) == 0 {
+		fmt.Printf("Sincronização: Valores da rede (%s) e DB (%s) já são iguais para chave '%s' no bloco %d.\n",
+			networkValue.String(), dbValue.String(), s.valueKey, target)
+		return networkValue, dbValue, target, nil
+	}
+
+	err = s.dbClient.SaveSyncedValue(ctx, s.valueKey, database.SyncedValue{
+		Value:       networkValue,
+		BlockNumber: target,
+		BlockHash:   targetHash.Hex(),
+	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("erro ao obter valor do DB para sincronização (chave %s): %w", SimpleStorageValueKey, err)
+		return nil, nil, 0, fmt.Errorf("erro ao salvar novo valor no DB durante sincronização (chave %s): %w", s.valueKey, err)
 	}
 
 	if networkValue.Cmp(dbValue) != 0 {
-		fmt.Printf("Sincronizando: Valor na rede (%s) difere do valor no DB (%s) para chave '%s'. Atualizando DB...\n",
-			networkValue.String(), dbValue.String(), SimpleStorageValueKey)
-		err = s.dbClient.SaveContractValue(ctx, SimpleStorageValueKey, networkValue)
+		fmt.Printf("Sincronizando: Valor na rede (%s) no bloco %d difere do valor no DB (%s) para chave '%s'. DB atualizado.\n",
+			networkValue.String(), target, dbValue.String(), s.valueKey)
+
+		err = s.dbClient.AppendValueHistory(ctx, database.ValueChangeRecord{
+			ContractAddress: s.contractAddressKey(),
+			Source:          database.HistorySourceSync,
+			BlockNumber:     target,
+			BlockHash:       targetHash.Hex(),
+			OldValue:        dbValue,
+			NewValue:        networkValue,
+		})
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("erro ao gravar histórico durante sincronização (chave %s): %w", s.valueKey, err)
+		}
+	}
+
+	return networkValue, networkValue, target, nil
+}
+
+// checkReorg verifica se o bloco da última sincronização continua na cadeia canônica e, se não continuar,
+// desfaz o histórico e o cursor do indexador até o último bloco em comum (ponto de fork)
+func (s *contractServiceImpl) checkReorg(ctx context.Context, stored *database.SyncedValue, head uint64) error {
+	if stored.BlockHash == "" || stored.BlockNumber > head {
+		return nil
+	}
+
+	canonical, err := s.contractClient.BlockHashAt(ctx, stored.BlockNumber)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar hash do bloco sincronizado %d: %w", stored.BlockNumber, err)
+	}
+	if canonical.Hex() == stored.BlockHash {
+		return nil
+	}
+
+	forkBlock, err := s.findForkPoint(ctx, stored.BlockNumber)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.dbClient.RollbackToBlock(ctx, s.contractAddressKey(), indexerCursorName(s.contractClient.Address()), forkBlock)
+	if err != nil {
+		return fmt.Errorf("erro ao desfazer sincronização até o bloco %d: %w", forkBlock, err)
+	}
+
+	fmt.Printf("Reorganização detectada: bloco %d mudou de %s para %s. Histórico desfeito até o bloco %d (%d registro(s) removido(s)).\n",
+		stored.BlockNumber, stored.BlockHash, canonical.Hex(), forkBlock, removed)
+	return nil
+}
+
+// findForkPoint procura, no histórico, o bloco mais alto abaixo de fromBlock cujo hash ainda é canônico
+func (s *contractServiceImpl) findForkPoint(ctx context.Context, fromBlock uint64) (uint64, error) {
+	const pageSize = 100
+
+	maxBlock := fromBlock
+	for maxBlock > 0 {
+		checkpoints, err := s.dbClient.ListHistoryCheckpoints(ctx, s.contractAddressKey(), maxBlock-1, pageSize)
 		if err != nil {
-			return nil, nil, fmt.Errorf("erro ao salvar novo valor no DB durante sincronização (chave %s): %w", SimpleStorageValueKey, err)
+			return 0, err
 		}
-		dbValue = networkValue
-	} else {
-		fmt.Printf("Sincronização: Valores da rede (%s) e DB (%s) já são iguais para chave '%s'.\n",
-			networkValue.String(), dbValue.String(), SimpleStorageValueKey)
+		if len(checkpoints) == 0 {
+			break
+		}
+
+		for _, checkpoint := range checkpoints {
+			canonical, err := s.contractClient.BlockHashAt(ctx, checkpoint.BlockNumber)
+			if err != nil {
+				return 0, fmt.Errorf("erro ao verificar hash do bloco %d durante busca do fork: %w", checkpoint.BlockNumber, err)
+			}
+			if canonical.Hex() == checkpoint.BlockHash {
+				return checkpoint.BlockNumber, nil
+			}
+		}
+		maxBlock = checkpoints[len(checkpoints)-1].BlockNumber
 	}
+	return 0, nil
+}
 
-	return networkValue, dbValue, nil
+// contractAddressKey retorna o endereço do contrato no formato gravado no banco
+func (s *contractServiceImpl) contractAddressKey() string {
+	return strings.ToLower(s.contractClient.Address().Hex())
 }
 
 // CheckContractValue busca o valor do contrato na rede e o compara com o valor no banco de dados
@@ -105,12 +383,52 @@ func (s *contractServiceImpl) CheckContractValue(ctx context.Context) (bool, *bi
 		return false, nil, nil, fmt.Errorf("erro ao obter valor da rede para verificação: %w", err)
 	}
 
-	dbValue, err := s.dbClient.GetContractValue(ctx, SimpleStorageValueKey)
+	dbValue, err := s.dbClient.GetContractValue(ctx, s.valueKey)
 	if err != nil {
-		return false, nil, nil, fmt.Errorf("erro ao obter valor do DB para verificação (chave %s): %w", SimpleStorageValueKey, err)
+		return false, nil, nil, fmt.Errorf("erro ao obter valor do DB para verificação (chave %s): %w", s.valueKey, err)
 	}
 
 	areEqual := networkValue.Cmp(dbValue) == 0
 
 	return areEqual, networkValue, dbValue, nil
 }
+
+// GetLatestBlockNumber retorna o número do último bloco da rede
+func (s *contractServiceImpl) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	return s.contractClient.LatestBlockNumber(ctx)
+}
+
+// NewHeads registra um assinante dos novos blocos da rede; a função retornada cancela o registro
+func (s *contractServiceImpl) NewHeads() (<-chan *types.Header, func()) {
+	return s.contractClient.NewHeads()
+}
+
+// GetTransactionStatus retorna o estado atual de uma transação
+func (s *contractServiceImpl) GetTransactionStatus(ctx context.Context, hash common.Hash) (*contract.TxStatus, error) {
+	status, err := s.contractClient.TransactionStatus(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar estado da transação %s: %w", hash.Hex(), err)
+	}
+	return status, nil
+}
+
+// WaitForTransaction aguarda até a transação ser minerada ou atingir um estado final
+func (s *contractServiceImpl) WaitForTransaction(ctx context.Context, hash common.Hash) (*contract.TxStatus, error) {
+	status, err := s.contractClient.WaitForTransaction(ctx, hash)
+	if err != nil {
+		return status, fmt.Errorf("erro ao aguardar transação %s: %w", hash.Hex(), err)
+	}
+	return status, nil
+}
+
+// GetValueHistory lista o histórico de valores do contrato com os filtros informados
+func (s *contractServiceImpl) GetValueHistory(ctx context.Context, filter database.HistoryFilter) ([]database.HistoryEntry, int, error) {
+	filter.ContractAddress = s.contractAddressKey()
+	filter.Setter = strings.ToLower(filter.Setter)
+
+	entries, total, err := s.dbClient.ListValueHistory(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao obter histórico de valores: %w", err)
+	}
+	return entries, total, nil
+}
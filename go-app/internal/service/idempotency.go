@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vmm2136/besu_challenge/go-app/internal/database"
+)
+
+var (
+	// ErrIdempotencyKeyConflict indica que a chave já foi usada com uma requisição diferente
+	ErrIdempotencyKeyConflict = errors.New("chave de idempotência já usada com outra requisição")
+	// ErrIdempotencyKeyInProgress indica que a requisição original da chave ainda não terminou
+	ErrIdempotencyKeyInProgress = errors.New("requisição com esta chave de idempotência ainda em andamento")
+)
+
+// IdempotentResponse é a resposta gravada para uma chave de idempotência
+type IdempotentResponse struct {
+	StatusCode int
+	Body       []byte
+	TxHash     string
+}
+
+// IdempotencyService garante que requisições repetidas com a mesma chave sejam executadas uma única vez
+type IdempotencyService interface {
+	// Begin registra a chave para a requisição identificada por requestHash. Se a chave já tiver uma resposta
+	// gravada para a mesma requisição, ela é retornada e a requisição não deve ser executada novamente.
+	// Uma reserva em andamento há mais do que o tempo limite (processo que caiu) é assumida pela nova requisição,
+	// a menos que uma transação já tenha sido enviada com a chave: nesse caso ela é devolvida como resposta.
+	Begin(ctx context.Context, key, requestHash string) (*IdempotentResponse, error)
+	// Finish encerra a requisição original da chave. A resposta é gravada se for 2xx ou se uma transação já tiver
+	// sido enviada com a chave, qualquer que seja o status; só nos outros casos a chave é liberada para nova tentativa.
+	Finish(ctx context.Context, key string, response IdempotentResponse) error
+}
+
+// idempotencyServiceImpl implementa IdempotencyService sobre a tabela idempotency_keys
+type idempotencyServiceImpl struct {
+	dbClient   database.DBClient
+	ttl        time.Duration
+	staleAfter time.Duration
+}
+
+// NewIdempotencyService cria o serviço de idempotência. As chaves expiram ttl após a primeira requisição;
+// requisições em andamento há mais de staleAfter são consideradas abandonadas.
+func NewIdempotencyService(dbClient database.DBClient, ttl, staleAfter time.Duration) (IdempotencyService, error) {
+	if dbClient == nil {
+		return nil, fmt.Errorf("cliente de banco de dados não pode ser nulo")
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("validade das chaves de idempotência deve ser maior que zero")
+	}
+	if staleAfter <= 0 {
+		return nil, fmt.Errorf("tempo limite das requisições idempotentes em andamento deve ser maior que zero")
+	}
+	return &idempotencyServiceImpl{dbClient: dbClient, ttl: ttl, staleAfter: staleAfter}, nil
+}
+
+// Begin implementa IdempotencyService
+func (s *idempotencyServiceImpl) Begin(ctx context.Context, key, requestHash string) (*IdempotentResponse, error) {
+	reserved, existing, err := s.dbClient.ReserveIdempotencyKey(ctx, key, requestHash, s.ttl)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyConflict
+	}
+	if existing.StatusCode != 0 {
+		return &IdempotentResponse{
+			StatusCode: existing.StatusCode,
+			Body:       existing.ResponseBody,
+			TxHash:     existing.TxHash,
+		}, nil
+	}
+
+	claimed, err := s.dbClient.ClaimStaleIdempotencyKey(ctx, key, s.staleAfter)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	// a requisição original foi abandonada; se ela chegou a enviar a transação, a repetição não pode enviar outra
+	response, err := s.sentTransactionResponse(ctx, key)
+	if err != nil || response == nil {
+		return nil, err
+	}
+	if err := s.complete(ctx, key, *response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Finish implementa IdempotencyService
+func (s *idempotencyServiceImpl) Finish(ctx context.Context, key string, response IdempotentResponse) error {
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return s.complete(ctx, key, response)
+	}
+
+	// um erro depois do envio (ex.: tempo esgotado em ?wait=true) não pode liberar a chave
+	sent, err := s.sentTransactionResponse(ctx, key)
+	if err != nil {
+		return err
+	}
+	if sent != nil {
+		return s.complete(ctx, key, *sent)
+	}
+	return s.dbClient.DeleteIdempotencyKey(ctx, key)
+}
+
+// sentTransactionResponse monta a resposta de uma transação já enviada com a chave, ou retorna nil se não houver
+func (s *idempotencyServiceImpl) sentTransactionResponse(ctx context.Context, key string) (*IdempotentResponse, error) {
+	record, err := s.dbClient.GetTransactionByIdempotencyKey(ctx, key)
+	if err != nil || record == nil {
+		return nil, err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"message": "Transação enviada com sucesso",
+		"tx_hash": record.TxHash,
+		"status":  record.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &IdempotentResponse{StatusCode: http.StatusAccepted, Body: body, TxHash: record.TxHash}, nil
+}
+
+func (s *idempotencyServiceImpl) complete(ctx context.Context, key string, response IdempotentResponse) error {
+	return s.dbClient.CompleteIdempotencyKey(ctx, key, response.StatusCode, response.Body, response.TxHash)
+}
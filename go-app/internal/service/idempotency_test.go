@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vmm2136/besu_challenge/go-app/internal/database"
+)
+
+// fakeIdempotencyKey é uma chave reservada no fakeIdempotencyDB. reservation identifica a reserva atual:
+// só as transações enviadas nela contam em GetTransactionByIdempotencyKey, como o reserved_at da tabela.
+type fakeIdempotencyKey struct {
+	record      database.IdempotencyRecord
+	reservation int
+	stale       bool
+	expired     bool
+}
+
+// fakeIdempotencyTx é uma transação do journal enviada com uma chave
+type fakeIdempotencyTx struct {
+	key         string
+	reservation int
+	record      database.TransactionRecord
+}
+
+// fakeIdempotencyDB guarda em memória as chaves de idempotência e as transações enviadas com elas.
+// Os demais métodos de DBClient não são usados pelo serviço de idempotência.
+type fakeIdempotencyDB struct {
+	database.DBClient
+	keys         map[string]*fakeIdempotencyKey
+	txs          []fakeIdempotencyTx
+	reservations int
+}
+
+func newFakeIdempotencyDB() *fakeIdempotencyDB {
+	return &fakeIdempotencyDB{keys: make(map[string]*fakeIdempotencyKey)}
+}
+
+func (db *fakeIdempotencyDB) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, ttl time.Duration) (bool, *database.IdempotencyRecord, error) {
+	if existing, ok := db.keys[key]; ok && !existing.expired {
+		record := existing.record
+		return false, &record, nil
+	}
+	db.reservations++
+	db.keys[key] = &fakeIdempotencyKey{
+		record:      database.IdempotencyRecord{Key: key, RequestHash: requestHash},
+		reservation: db.reservations,
+	}
+	return true, nil, nil
+}
+
+func (db *fakeIdempotencyDB) CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, responseBody []byte, txHash string) error {
+	if existing, ok := db.keys[key]; ok {
+		existing.record.StatusCode = statusCode
+		existing.record.ResponseBody = responseBody
+		existing.record.TxHash = txHash
+	}
+	return nil
+}
+
+func (db *fakeIdempotencyDB) ClaimStaleIdempotencyKey(ctx context.Context, key string, staleAfter time.Duration) (bool, error) {
+	existing, ok := db.keys[key]
+	if !ok || existing.record.StatusCode != 0 || !existing.stale {
+		return false, nil
+	}
+	existing.stale = false
+	return true, nil
+}
+
+func (db *fakeIdempotencyDB) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	delete(db.keys, key)
+	return nil
+}
+
+func (db *fakeIdempotencyDB) GetTransactionByIdempotencyKey(ctx context.Context, key string) (*database.TransactionRecord, error) {
+	existing, ok := db.keys[key]
+	if !ok {
+		return nil, nil
+	}
+	for _, tx := range db.txs {
+		if tx.key == key && tx.reservation == existing.reservation {
+			record := tx.record
+			return &record, nil
+		}
+	}
+	return nil, nil
+}
+
+// send registra no journal uma transação enviada com a chave na reserva atual
+func (db *fakeIdempotencyDB) send(key, txHash string) {
+	db.txs = append(db.txs, fakeIdempotencyTx{
+		key:         key,
+		reservation: db.keys[key].reservation,
+		record:      database.TransactionRecord{TxHash: txHash, IdempotencyKey: key, Status: "pending"},
+	})
+}
+
+// idempotencyStep é um passo do teste sobre a chave "k": begin (com hash), finish (com status e, se não vazio,
+// txHash gravado na resposta), send (envia a transação txHash), stale (a reserva foi abandonada) ou expire (TTL).
+// Nos passos begin, wantStatus é o status da resposta repetida (0 quando a requisição deve ser executada).
+type idempotencyStep struct {
+	op         string
+	hash       string
+	status     int
+	txHash     string
+	wantErr    error
+	wantStatus int
+	wantTxHash string
+}
+
+func TestIdempotencyService(t *testing.T) {
+	const key = "k"
+
+	tests := []struct {
+		name  string
+		steps []idempotencyStep
+		// wantKey indica se a chave deve continuar registrada ao final; wantStored é o status gravado nela
+		wantKey    bool
+		wantStored int
+		wantTxHash string
+	}{
+		{
+			name: "resposta 2xx é gravada e repetida",
+			steps: []idempotencyStep{
+				{op: "begin", hash: "a"},
+				{op: "send", txHash: "0x01"},
+				{op: "finish", status: http.StatusAccepted, txHash: "0x01"},
+				{op: "begin", hash: "a", wantStatus: http.StatusAccepted, wantTxHash: "0x01"},
+			},
+			wantKey: true, wantStored: http.StatusAccepted, wantTxHash: "0x01",
+		},
+		{
+			name: "mesma chave com outra requisição",
+			steps: []idempotencyStep{
+				{op: "begin", hash: "a"},
+				{op: "finish", status: http.StatusOK},
+				{op: "begin", hash: "b", wantErr: ErrIdempotencyKeyConflict},
+			},
+			wantKey: true, wantStored: http.StatusOK,
+		},
+		{
+			name: "erro antes do envio libera a chave",
+			steps: []idempotencyStep{
+				{op: "begin", hash: "a"},
+				{op: "finish", status: http.StatusBadRequest},
+				{op: "begin", hash: "a"},
+			},
+			wantKey: true,
+		},
+		{
+			name: "erro depois do envio grava a transação enviada",
+			steps: []idempotencyStep{
+				{op: "begin", hash: "a"},
+				{op: "send", txHash: "0x01"},
+				{op: "finish", status: http.StatusGatewayTimeout},
+				{op: "begin", hash: "a", wantStatus: http.StatusAccepted, wantTxHash: "0x01"},
+			},
+			wantKey: true, wantStored: http.StatusAccepted, wantTxHash: "0x01",
+		},
+		{
+			name: "requisição ainda em andamento",
+			steps: []idempotencyStep{
+				{op: "begin", hash: "a"},
+				{op: "begin", hash: "a", wantErr: ErrIdempotencyKeyInProgress},
+			},
+			wantKey: true,
+		},
+		{
+			name: "reserva abandonada antes do envio é assumida",
+			steps: []idempotencyStep{
+				{op: "begin", hash: "a"},
+				{op: "stale"},
+				{op: "begin", hash: "a"},
+				{op: "send", txHash: "0x02"},
+				{op: "finish", status: http.StatusAccepted, txHash: "0x02"},
+			},
+			wantKey: true, wantStored: http.StatusAccepted, wantTxHash: "0x02",
+		},
+		{
+			name: "reserva abandonada depois do envio devolve a transação enviada",
+			steps: []idempotencyStep{
+				{op: "begin", hash: "a"},
+				{op: "send", txHash: "0x01"},
+				{op: "stale"},
+				{op: "begin", hash: "a", wantStatus: http.StatusAccepted, wantTxHash: "0x01"},
+			},
+			wantKey: true, wantStored: http.StatusAccepted, wantTxHash: "0x01",
+		},
+		{
+			name: "chave reutilizada após o TTL ignora a transação do uso anterior",
+			steps: []idempotencyStep{
+				{op: "begin", hash: "a"},
+				{op: "send", txHash: "0x01"},
+				{op: "finish", status: http.StatusAccepted, txHash: "0x01"},
+				{op: "expire"},
+				{op: "begin", hash: "b"},
+				{op: "finish", status: http.StatusBadRequest},
+			},
+			wantKey: false,
+		},
+		{
+			name: "chave reutilizada após o TTL grava a transação nova",
+			steps: []idempotencyStep{
+				{op: "begin", hash: "a"},
+				{op: "send", txHash: "0x01"},
+				{op: "finish", status: http.StatusAccepted, txHash: "0x01"},
+				{op: "expire"},
+				{op: "begin", hash: "b"},
+				{op: "send", txHash: "0x02"},
+				{op: "finish", status: http.StatusGatewayTimeout},
+			},
+			wantKey: true, wantStored: http.StatusAccepted, wantTxHash: "0x02",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newFakeIdempotencyDB()
+			svc, err := NewIdempotencyService(db, time.Hour, time.Minute)
+			if err != nil {
+				t.Fatalf("NewIdempotencyService: %v", err)
+			}
+			ctx := context.Background()
+
+			for i, step := range tt.steps {
+				switch step.op {
+				case "begin":
+					replay, err := svc.Begin(ctx, key, step.hash)
+					if !errors.Is(err, step.wantErr) {
+						t.Fatalf("passo %d: erro %v, esperado %v", i, err, step.wantErr)
+					}
+					if step.wantErr != nil {
+						continue
+					}
+					if step.wantStatus == 0 {
+						if replay != nil {
+							t.Fatalf("passo %d: resposta repetida %d, esperado executar a requisição", i, replay.StatusCode)
+						}
+						continue
+					}
+					if replay == nil {
+						t.Fatalf("passo %d: esperado repetir a resposta %d", i, step.wantStatus)
+					}
+					if replay.StatusCode != step.wantStatus || replay.TxHash != step.wantTxHash {
+						t.Errorf("passo %d: resposta %d (%s), esperado %d (%s)", i, replay.StatusCode, replay.TxHash, step.wantStatus, step.wantTxHash)
+					}
+				case "finish":
+					response := IdempotentResponse{StatusCode: step.status, Body: []byte(`{}`), TxHash: step.txHash}
+					if err := svc.Finish(ctx, key, response); err != nil {
+						t.Fatalf("passo %d: Finish: %v", i, err)
+					}
+				case "send":
+					db.send(key, step.txHash)
+				case "stale":
+					db.keys[key].stale = true
+				case "expire":
+					db.keys[key].expired = true
+				}
+			}
+
+			stored, ok := db.keys[key]
+			if ok != tt.wantKey {
+				t.Fatalf("chave registrada: %v, esperado %v", ok, tt.wantKey)
+			}
+			if !ok {
+				return
+			}
+			if stored.record.StatusCode != tt.wantStored || stored.record.TxHash != tt.wantTxHash {
+				t.Errorf("resposta gravada %d (%s), esperado %d (%s)", stored.record.StatusCode, stored.record.TxHash, tt.wantStored, tt.wantTxHash)
+			}
+			if tt.wantTxHash != "" && tt.wantStored != 0 {
+				var body struct {
+					TxHash string `json:"tx_hash"`
+				}
+				if err := json.Unmarshal(stored.record.ResponseBody, &body); err == nil && body.TxHash != "" && body.TxHash != tt.wantTxHash {
+					t.Errorf("corpo gravado aponta para %s, esperado %s", body.TxHash, tt.wantTxHash)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
+	"github.com/vmm2136/besu_challenge/go-app/internal/database"
+)
+
+// Indexer lê os eventos ValueChanged do contrato a partir de um cursor salvo no banco e os grava no histórico.
+// Um novo lote é lido a cada novo bloco da rede e, na falta de avisos de bloco, a cada interval.
+type Indexer struct {
+	contractClient contract.ContractClient
+	dbClient       database.DBClient
+	cursorName     string
+	startBlock     uint64
+	batchSize      uint64
+	confirmations  uint64
+	interval       time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewIndexer cria um Indexer que começa em startBlock quando ainda não existe cursor salvo
+// e só indexa blocos com pelo menos confirmations blocos acima deles.
+func NewIndexer(client contract.ContractClient, dbClient database.DBClient, startBlock, batchSize, confirmations uint64, interval time.Duration) (*Indexer, error) {
+	if client == nil {
+		return nil, fmt.Errorf("cliente do contrato não pode ser nulo")
+	}
+	if dbClient == nil {
+		return nil, fmt.Errorf("cliente de banco de dados não pode ser nulo")
+	}
+	if batchSize == 0 {
+		return nil, fmt.Errorf("tamanho do lote do indexador deve ser positivo")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("intervalo do indexador deve ser positivo")
+	}
+	return &Indexer{
+		contractClient: client,
+		dbClient:       dbClient,
+		cursorName:     indexerCursorName(client.Address()),
+		startBlock:     startBlock,
+		batchSize:      batchSize,
+		confirmations:  confirmations,
+		interval:       interval,
+	}, nil
+}
+
+// Start inicia o indexador em uma goroutine; ele para quando ctx é cancelado ou Stop é chamado
+func (i *Indexer) Start(ctx context.Context) {
+	ctx, i.cancel = context.WithCancel(ctx)
+	i.done = make(chan struct{})
+	go i.run(ctx)
+}
+
+// Stop interrompe o indexador e aguarda o lote em andamento terminar
+func (i *Indexer) Stop() {
+	if i.cancel == nil {
+		return
+	}
+	i.cancel()
+	<-i.done
+}
+
+func (i *Indexer) run(ctx context.Context) {
+	defer close(i.done)
+
+	fmt.Printf("Indexador de eventos iniciado (cursor %s, lote de %d blocos)\n", i.cursorName, i.batchSize)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	heads, unsubscribe := i.contractClient.NewHeads()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("Indexador de eventos finalizado.")
+			return
+		case <-timer.C:
+		case <-heads:
+		}
+
+		caughtUp, err := i.IndexNextBatch(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			fmt.Printf("Erro no indexador de eventos: %v\n", err)
+			timer.Reset(i.interval)
+		case caughtUp:
+			timer.Reset(i.interval)
+		default:
+			timer.Reset(0)
+		}
+	}
+}
+
+// IndexNextBatch indexa o próximo lote de blocos a partir do cursor e indica se o indexador alcançou o último bloco
+func (i *Indexer) IndexNextBatch(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cursor, found, err := i.dbClient.GetIndexerCursor(ctx, i.cursorName)
+	if err != nil {
+		return false, err
+	}
+	fromBlock := i.startBlock
+	if found {
+		fromBlock = cursor + 1
+	}
+
+	head, err := i.contractClient.LatestBlockNumber(ctx)
+	if err != nil {
+		return false, err
+	}
+	if head < i.confirmations {
+		return true, nil
+	}
+	head -= i.confirmations
+	if fromBlock > head {
+		return true, nil
+	}
+
+	toBlock := fromBlock + i.batchSize - 1
+	if toBlock > head {
+		toBlock = head
+	}
+
+	events, err := i.contractClient.FilterValueChanged(ctx, fromBlock, toBlock)
+	if err != nil {
+		return false, err
+	}
+
+	records := make([]database.ValueChangeRecord, 0, len(events))
+	for _, event := range events {
+		records = append(records, valueChangeRecordFromEvent(event, database.HistorySourceEvent))
+	}
+
+	if err := i.dbClient.SaveValueChanges(ctx, i.cursorName, toBlock, records); err != nil {
+		return false, err
+	}
+
+	if len(records) > 0 {
+		fmt.Printf("Indexador: %d evento(s) '%s' gravados entre os blocos %d e %d\n", len(records), contract.ValueChangedEventName, fromBlock, toBlock)
+	}
+	return toBlock == head, nil
+}
+
+// indexerCursorName retorna o nome do cursor do indexador de eventos de um contrato
+func indexerCursorName(address common.Address) string {
+	return "value_changed:" + strings.ToLower(address.Hex())
+}
+
+// valueChangeRecordFromEvent converte um evento decodificado no registro gravado no histórico
+func valueChangeRecordFromEvent(event contract.ValueChangedEvent, source string) database.ValueChangeRecord {
+	logIndex := event.LogIndex
+	return database.ValueChangeRecord{
+		ContractAddress: strings.ToLower(event.ContractAddress.Hex()),
+		Source:          source,
+		BlockNumber:     event.BlockNumber,
+		BlockHash:       event.BlockHash.Hex(),
+		TxHash:          event.TxHash.Hex(),
+		LogIndex:        &logIndex,
+		Setter:          strings.ToLower(event.Setter.Hex()),
+		OldValue:        event.OldValue,
+		NewValue:        event.NewValue,
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
+)
+
+var (
+	// ErrUnknownContract indica que a transação assinada pelo cliente não é destinada a um contrato registrado
+	ErrUnknownContract = errors.New("destino da transação não é um contrato registrado")
+	// ErrManagedSender indica que a transação assinada pelo cliente vem de uma conta do pool de transatores,
+	// cujos nonces são reservados pelo NonceManager da API
+	ErrManagedSender = errors.New("remetente é uma conta do pool de transatores da API")
+)
+
+// RawTxResult é o resultado do envio de uma transação assinada pelo cliente
+type RawTxResult struct {
+	Contract string
+	contract.RawTransaction
+}
+
+// RawTxService recebe transações assinadas pelos próprios clientes, que mantêm as suas chaves,
+// e as envia aos contratos do registro
+type RawTxService interface {
+	// Submit decodifica a transação, encontra o contrato de destino, valida e envia a transação
+	Submit(ctx context.Context, raw []byte) (*RawTxResult, error)
+}
+
+// rawTxServiceImpl implementa RawTxService
+type rawTxServiceImpl struct {
+	registry    *Registry
+	transactors *contract.TransactorPool
+	allowed     map[string]bool // "Contrato.funcao" ou "funcao" (qualquer contrato); vazio recusa todas as funções
+}
+
+// NewRawTxService cria o serviço de transações assinadas pelo cliente.
+// allowedMethods são as funções aceitas, no formato "Contrato.funcao" ou apenas "funcao" para qualquer contrato;
+// uma lista vazia recusa todas. Cada item precisa ser uma função que altera o estado de um contrato já registrado.
+// Transações de contas do pool de transatores são recusadas, para não disputar nonces com as escritas assinadas
+// pela API.
+func NewRawTxService(registry *Registry, transactors *contract.TransactorPool, allowedMethods []string) (RawTxService, error) {
+	if registry == nil {
+		return nil, fmt.Errorf("registro de contratos não pode ser nulo")
+	}
+	if transactors == nil {
+		return nil, fmt.Errorf("pool de transatores para o serviço não pode ser nulo")
+	}
+	allowed := make(map[string]bool, len(allowedMethods))
+	for _, method := range allowedMethods {
+		method = strings.TrimSpace(method)
+		if method == "" {
+			continue
+		}
+		if !hasMutatingMethod(registry, method) {
+			return nil, fmt.Errorf("função '%s' de RAW_TX_ALLOWED_METHODS não existe ou não altera o estado em nenhum contrato registrado", method)
+		}
+		allowed[method] = true
+	}
+	return &rawTxServiceImpl{registry: registry, transactors: transactors, allowed: allowed}, nil
+}
+
+// Submit implementa RawTxService
+func (s *rawTxServiceImpl) Submit(ctx context.Context, raw []byte) (*RawTxResult, error) {
+	tx, err := contract.DecodeRawTransaction(raw)
+	if err != nil {
+		return nil, err
+	}
+	if tx.To() == nil {
+		return nil, fmt.Errorf("%w: criação de contratos não é aceita", ErrUnknownContract)
+	}
+
+	// o remetente é recuperado com o chain ID da própria transação; a rede é conferida pelo contrato
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: assinatura inválida: %v", contract.ErrInvalidRawTx, err)
+	}
+	if _, managed := s.transactors.SignerFor(from); managed {
+		return nil, fmt.Errorf("%w: %s", ErrManagedSender, from.Hex())
+	}
+
+	svc, ok := s.registry.GetByAddress(*tx.To())
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownContract, tx.To().Hex())
+	}
+
+	sent, err := svc.SendRawTransaction(ctx, tx, s.allowedFor(svc.ContractName()))
+	if err != nil {
+		return nil, err
+	}
+	return &RawTxResult{Contract: svc.ContractName(), RawTransaction: sent}, nil
+}
+
+// allowedFor retorna o filtro de funções aceitas do contrato
+func (s *rawTxServiceImpl) allowedFor(contractName string) func(method string) bool {
+	return func(method string) bool {
+		return s.allowed[method] || s.allowed[contractName+"."+method]
+	}
+}
+
+// hasMutatingMethod diz se a função de "Contrato.funcao" ou, sem contrato, de algum contrato do registro existe no ABI
+// e altera o estado
+func hasMutatingMethod(registry *Registry, entry string) bool {
+	names := registry.Names()
+	method := entry
+	if i := strings.LastIndex(entry, "."); i >= 0 {
+		names, method = []string{entry[:i]}, entry[i+1:]
+	}
+	for _, name := range names {
+		svc, ok := registry.Get(name)
+		if !ok {
+			continue
+		}
+		for _, info := range svc.ListMethods() {
+			if info.Name == method && info.StateMutability != "view" && info.StateMutability != "pure" {
+				return true
+			}
+		}
+	}
+	return false
+}
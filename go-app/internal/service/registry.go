@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Registry agrupa o ContractService e o SyncWorker de cada contrato, indexados pelo nome do contrato
+type Registry struct {
+	mu          sync.RWMutex
+	services    map[string]ContractService
+	workers     map[string]*SyncWorker
+	names       []string
+	defaultName string
+}
+
+// NewRegistry cria um Registry vazio. defaultName é o contrato atendido pelas rotas sem /contracts/{name}.
+func NewRegistry(defaultName string) *Registry {
+	return &Registry{
+		services:    make(map[string]ContractService),
+		workers:     make(map[string]*SyncWorker),
+		defaultName: defaultName,
+	}
+}
+
+// Register adiciona o serviço de um contrato; syncWorker pode ser nulo quando a sincronização está desabilitada
+func (r *Registry) Register(svc ContractService, syncWorker *SyncWorker) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := svc.ContractName()
+	if _, exists := r.services[name]; exists {
+		return fmt.Errorf("contrato %s já registrado", name)
+	}
+	r.services[name] = svc
+	if syncWorker != nil {
+		r.workers[name] = syncWorker
+	}
+	r.names = append(r.names, name)
+	return nil
+}
+
+// Get retorna o serviço do contrato com o nome informado
+func (r *Registry) Get(name string) (ContractService, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	svc, ok := r.services[name]
+	return svc, ok
+}
+
+// GetByAddress retorna o serviço do contrato implantado no endereço informado
+func (r *Registry) GetByAddress(address common.Address) (ContractService, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, name := range r.names {
+		if svc := r.services[name]; svc.ContractAddress() == address {
+			return svc, true
+		}
+	}
+	return nil, false
+}
+
+// Default retorna o serviço do contrato padrão
+func (r *Registry) Default() (ContractService, bool) {
+	return r.Get(r.defaultName)
+}
+
+// DefaultName retorna o nome do contrato padrão
+func (r *Registry) DefaultName() string {
+	return r.defaultName
+}
+
+// SyncWorker retorna o worker de sincronização do contrato, ou nil se ele não existir
+func (r *Registry) SyncWorker(name string) *SyncWorker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.workers[name]
+}
+
+// Names retorna os nomes dos contratos na ordem em que foram registrados
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.names...)
+}
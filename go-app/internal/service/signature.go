@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
+	"github.com/vmm2136/besu_challenge/go-app/internal/pkg/ethutils"
+)
+
+var (
+	// ErrSigningDisabled indica que a assinatura de mensagens com as contas do pool está desabilitada
+	ErrSigningDisabled = errors.New("assinatura de mensagens desabilitada")
+	// ErrUnknownSigner indica que a conta pedida não pertence ao pool de transatores
+	ErrUnknownSigner = errors.New("conta não pertence ao pool de transatores")
+	// ErrSignerRequired indica que a conta que assina não foi informada
+	ErrSignerRequired = errors.New("conta que assina é obrigatória")
+	// ErrSigningUnauthorized indica que o token de acesso aos endpoints de assinatura está ausente ou incorreto
+	ErrSigningUnauthorized = errors.New("token de assinatura ausente ou inválido")
+)
+
+// Signature é uma assinatura EIP-191 ou EIP-712, com o hash assinado e a conta que assinou
+type Signature struct {
+	Address   common.Address
+	Hash      common.Hash
+	Signature []byte
+}
+
+// SignatureService assina mensagens (EIP-191) e dados tipados (EIP-712) com as contas do pool de transatores
+// e recupera a conta que assinou uma mensagem, para desafios de login e aprovações off-chain
+type SignatureService interface {
+	// Authorize confere o token de acesso exigido para assinar com as contas do pool
+	Authorize(token string) error
+	// SignMessage assina a mensagem com a conta address do pool
+	SignMessage(ctx context.Context, address common.Address, message []byte) (*Signature, error)
+	// SignTypedData assina os dados tipados com a conta address do pool
+	SignTypedData(ctx context.Context, address common.Address, typedData apitypes.TypedData) (*Signature, error)
+	// RecoverMessage recupera a conta que assinou a mensagem
+	RecoverMessage(message, signature []byte) (*Signature, error)
+	// RecoverTypedData recupera a conta que assinou os dados tipados
+	RecoverTypedData(typedData apitypes.TypedData, signature []byte) (*Signature, error)
+}
+
+// signatureServiceImpl implementa SignatureService
+type signatureServiceImpl struct {
+	transactors    *contract.TransactorPool
+	signingEnabled bool
+	apiToken       string
+}
+
+// NewSignatureService cria o serviço de assinaturas. Com signingEnabled falso apenas a recuperação de assinaturas
+// funciona, e nenhuma conta do pool assina mensagens. Com a assinatura habilitada, apiToken é obrigatório e
+// Authorize só aceita esse token.
+func NewSignatureService(transactors *contract.TransactorPool, signingEnabled bool, apiToken string) (SignatureService, error) {
+	if transactors == nil {
+		return nil, fmt.Errorf("pool de transatores para o serviço não pode ser nulo")
+	}
+	if signingEnabled && apiToken == "" {
+		return nil, fmt.Errorf("token de acesso é obrigatório com a assinatura de mensagens habilitada")
+	}
+	return &signatureServiceImpl{transactors: transactors, signingEnabled: signingEnabled, apiToken: apiToken}, nil
+}
+
+// Authorize implementa SignatureService
+func (s *signatureServiceImpl) Authorize(token string) error {
+	if !s.signingEnabled {
+		return ErrSigningDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.apiToken)) != 1 {
+		return ErrSigningUnauthorized
+	}
+	return nil
+}
+
+// SignMessage implementa SignatureService
+func (s *signatureServiceImpl) SignMessage(ctx context.Context, address common.Address, message []byte) (*Signature, error) {
+	signer, err := s.signer(address)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.SignMessage(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{Address: signer.Address(), Hash: ethutils.MessageHash(message), Signature: signature}, nil
+}
+
+// SignTypedData implementa SignatureService
+func (s *signatureServiceImpl) SignTypedData(ctx context.Context, address common.Address, typedData apitypes.TypedData) (*Signature, error) {
+	hash, err := ethutils.TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := s.signer(address)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.SignTypedData(ctx, typedData)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{Address: signer.Address(), Hash: hash, Signature: signature}, nil
+}
+
+// RecoverMessage implementa SignatureService
+func (s *signatureServiceImpl) RecoverMessage(message, signature []byte) (*Signature, error) {
+	return recoverSignature(ethutils.MessageHash(message), signature)
+}
+
+// RecoverTypedData implementa SignatureService
+func (s *signatureServiceImpl) RecoverTypedData(typedData apitypes.TypedData, signature []byte) (*Signature, error) {
+	hash, err := ethutils.TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	return recoverSignature(hash, signature)
+}
+
+// signer retorna a conta do pool que assina a mensagem
+func (s *signatureServiceImpl) signer(address common.Address) (ethutils.Signer, error) {
+	if !s.signingEnabled {
+		return nil, ErrSigningDisabled
+	}
+	if address == (common.Address{}) {
+		return nil, ErrSignerRequired
+	}
+	signer, ok := s.transactors.SignerFor(address)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSigner, address.Hex())
+	}
+	return signer, nil
+}
+
+func recoverSignature(hash common.Hash, signature []byte) (*Signature, error) {
+	address, err := ethutils.RecoverSigner(hash, signature)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{Address: address, Hash: hash, Signature: signature}, nil
+}
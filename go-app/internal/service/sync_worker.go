@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Modos de disparo do SyncWorker
+const (
+	SyncModeInterval = "interval" // sincroniza a cada intervalo
+	SyncModeBlock    = "block"    // sincroniza sempre que um novo bloco é produzido
+)
+
+// SyncStatus descreve a última execução do SyncWorker
+type SyncStatus struct {
+	Enabled             bool
+	Mode                string
+	Running             bool
+	LastRunAt           time.Time
+	LastSuccessAt       time.Time
+	LastError           string
+	ConsecutiveFailures int
+	NetworkValue        string
+	DatabaseValue       string
+	SyncedBlock         uint64 // bloco lido na última sincronização (último bloco menos as confirmações)
+	HeadBlock           uint64
+}
+
+// LagBlocks retorna quantos blocos a rede avançou desde a última sincronização bem sucedida
+func (s SyncStatus) LagBlocks() uint64 {
+	if s.HeadBlock < s.SyncedBlock {
+		return 0
+	}
+	return s.HeadBlock - s.SyncedBlock
+}
+
+// SyncWorker mantém o banco de dados sincronizado com a rede executando SyncContractValue em segundo plano
+type SyncWorker struct {
+	service    ContractService
+	mode       string
+	interval   time.Duration
+	maxBackoff time.Duration
+
+	mu         sync.RWMutex
+	status     SyncStatus
+	syncedHead uint64 // último bloco da rede na última sincronização bem sucedida
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// NewSyncWorker cria um SyncWorker.
+// No modo SyncModeBlock a sincronização é disparada a cada novo bloco recebido e o interval é a consulta de reserva
+// do último bloco, caso algum aviso de bloco se perca.
+func NewSyncWorker(svc ContractService, mode string, interval, maxBackoff time.Duration) (*SyncWorker, error) {
+	if svc == nil {
+		return nil, fmt.Errorf("serviço do contrato não pode ser nulo")
+	}
+	if mode != SyncModeInterval && mode != SyncModeBlock {
+		return nil, fmt.Errorf("modo de sincronização inválido: %s", mode)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("intervalo de sincronização deve ser positivo")
+	}
+	if maxBackoff < interval {
+		maxBackoff = interval
+	}
+	return &SyncWorker{
+		service:    svc,
+		mode:       mode,
+		interval:   interval,
+		maxBackoff: maxBackoff,
+		status:     SyncStatus{Enabled: true, Mode: mode},
+	}, nil
+}
+
+// Start inicia o worker em uma goroutine; ele para quando ctx é cancelado ou Stop é chamado
+func (w *SyncWorker) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	w.mu.Lock()
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	w.status.Running = true
+	w.mu.Unlock()
+
+	go w.run(ctx)
+}
+
+// Stop interrompe o worker e aguarda a execução em andamento terminar
+func (w *SyncWorker) Stop() {
+	w.mu.RLock()
+	cancel, done := w.cancel, w.done
+	w.mu.RUnlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// Status retorna uma cópia do estado atual do worker
+func (w *SyncWorker) Status() SyncStatus {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.status
+}
+
+func (w *SyncWorker) run(ctx context.Context) {
+	defer func() {
+		w.mu.Lock()
+		w.status.Running = false
+		w.mu.Unlock()
+		close(w.done)
+	}()
+
+	fmt.Printf("Worker de sincronização iniciado (modo %s, intervalo %s)\n", w.mode, w.interval)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	// no modo interval heads fica nulo e nunca dispara
+	var heads <-chan *types.Header
+	if w.mode == SyncModeBlock {
+		var unsubscribe func()
+		heads, unsubscribe = w.service.NewHeads()
+		defer unsubscribe()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("Worker de sincronização finalizado.")
+			return
+		case <-timer.C:
+		case <-heads:
+			// durante o backoff os novos blocos não antecipam a próxima tentativa
+			if w.Status().ConsecutiveFailures > 0 {
+				continue
+			}
+		}
+
+		timer.Reset(w.runOnce(ctx))
+	}
+}
+
+// runOnce executa um ciclo de sincronização e retorna quanto tempo esperar até o próximo
+func (w *SyncWorker) runOnce(ctx context.Context) time.Duration {
+	runCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	head, err := w.service.GetLatestBlockNumber(runCtx)
+	if err != nil {
+		return w.fail(ctx, fmt.Errorf("erro ao obter último bloco: %w", err))
+	}
+
+	w.mu.Lock()
+	w.status.HeadBlock = head
+	alreadySynced := w.mode == SyncModeBlock && w.status.ConsecutiveFailures == 0 &&
+		!w.status.LastSuccessAt.IsZero() && w.syncedHead == head
+	w.mu.Unlock()
+
+	if alreadySynced {
+		return w.interval
+	}
+
+	networkValue, dbValue, block, err := w.service.SyncContractValue(runCtx)
+	if err != nil {
+		return w.fail(ctx, err)
+	}
+
+	now := time.Now()
+	w.mu.Lock()
+	w.status.LastRunAt = now
+	w.status.LastSuccessAt = now
+	w.status.LastError = ""
+	w.status.ConsecutiveFailures = 0
+	w.status.NetworkValue = networkValue.String()
+	w.status.DatabaseValue = dbValue.String()
+	w.status.SyncedBlock = block
+	w.syncedHead = head
+	w.mu.Unlock()
+
+	return w.interval
+}
+
+// fail registra a falha e calcula o próximo atraso com backoff exponencial e jitter
+func (w *SyncWorker) fail(ctx context.Context, err error) time.Duration {
+	if ctx.Err() != nil {
+		return w.interval
+	}
+
+	w.mu.Lock()
+	w.status.LastRunAt = time.Now()
+	w.status.LastError = err.Error()
+	w.status.ConsecutiveFailures++
+	failures := w.status.ConsecutiveFailures
+	w.mu.Unlock()
+
+	backoff := w.interval
+	for i := 1; i < failures && backoff < w.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > w.maxBackoff {
+		backoff = w.maxBackoff
+	}
+	// jitter entre 50% e 150% do backoff para evitar que várias instâncias sincronizem ao mesmo tempo
+	delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff)+1))
+
+	fmt.Printf("Erro no worker de sincronização (falha %d, nova tentativa em %s): %v\n", failures, delay.Round(time.Millisecond), err)
+	return delay
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
+	"github.com/vmm2136/besu_challenge/go-app/internal/database"
+)
+
+// openTxStates são os estados das transações que a recuperação na inicialização precisa reconciliar com o nó
+var openTxStates = []string{
+	string(contract.TxStateSubmitted),
+	string(contract.TxStatePending),
+	string(contract.TxStateMined),
+	string(contract.TxStateReplaced),
+}
+
+// txJournal implementa contract.TxJournal sobre a tabela transactions do banco de dados
+type txJournal struct {
+	dbClient database.DBClient
+}
+
+// NewTxJournal cria o journal de transações persistido no banco de dados
+func NewTxJournal(dbClient database.DBClient) contract.TxJournal {
+	return &txJournal{dbClient: dbClient}
+}
+
+// SaveTransaction grava uma transação assinada antes do envio
+func (j *txJournal) SaveTransaction(ctx context.Context, record contract.TxRecord) error {
+	toAddress := ""
+	if record.To != nil {
+		toAddress = strings.ToLower(record.To.Hex())
+	}
+
+	return j.dbClient.InsertTransaction(ctx, database.TransactionRecord{
+		TxHash:         record.Hash.Hex(),
+		FromAddress:    strings.ToLower(record.From.Hex()),
+		ToAddress:      toAddress,
+		Nonce:          record.Nonce,
+		TxType:         record.Type,
+		RawTx:          record.Raw,
+		GasLimit:       record.GasLimit,
+		GasPrice:       record.GasPrice,
+		GasTipCap:      record.GasTipCap,
+		GasFeeCap:      record.GasFeeCap,
+		Value:          record.Value,
+		Method:         record.Method,
+		Requester:      record.Requester,
+		IdempotencyKey: record.IdempotencyKey,
+		Status:         string(record.State),
+		Replaces:       hashOrEmpty(record.Replaces),
+	})
+}
+
+// UpdateTransactionState persiste o estado atual de uma transação
+func (j *txJournal) UpdateTransactionState(ctx context.Context, status contract.TxStatus) error {
+	update := database.TransactionStatusUpdate{
+		TxHash:     status.Hash.Hex(),
+		Status:     string(status.State),
+		BlockHash:  hashOrEmpty(status.BlockHash),
+		ReplacedBy: hashOrEmpty(status.ReplacedBy),
+		Replaces:   hashOrEmpty(status.Replaces),
+	}
+	if status.BlockHash != (common.Hash{}) {
+		blockNumber := status.BlockNumber
+		update.BlockNumber = &blockNumber
+	}
+	return j.dbClient.UpdateTransactionStatus(ctx, update)
+}
+
+// ListOpenTransactions lista as transações que ainda não atingiram um estado final
+func (j *txJournal) ListOpenTransactions(ctx context.Context) ([]contract.TxRecord, error) {
+	rows, err := j.dbClient.ListTransactionsByStatus(ctx, openTxStates)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]contract.TxRecord, 0, len(rows))
+	for _, row := range rows {
+		record := contract.TxRecord{
+			Hash:           common.HexToHash(row.TxHash),
+			From:           common.HexToAddress(row.FromAddress),
+			Nonce:          row.Nonce,
+			Type:           row.TxType,
+			Raw:            row.RawTx,
+			GasLimit:       row.GasLimit,
+			GasPrice:       row.GasPrice,
+			GasTipCap:      row.GasTipCap,
+			GasFeeCap:      row.GasFeeCap,
+			Value:          row.Value,
+			Method:         row.Method,
+			Requester:      row.Requester,
+			IdempotencyKey: row.IdempotencyKey,
+			State:          contract.TxState(row.Status),
+			BlockHash:      common.HexToHash(row.BlockHash),
+			ReplacedBy:     common.HexToHash(row.ReplacedBy),
+			Replaces:       common.HexToHash(row.Replaces),
+			SubmittedAt:    row.CreatedAt,
+		}
+		if row.ToAddress != "" {
+			to := common.HexToAddress(row.ToAddress)
+			record.To = &to
+		}
+		if row.BlockNumber != nil {
+			record.BlockNumber = *row.BlockNumber
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func hashOrEmpty(hash common.Hash) string {
+	if hash == (common.Hash{}) {
+		return ""
+	}
+	return hash.Hex()
+}
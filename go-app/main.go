@@ -1,17 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/joho/godotenv"
 	"github.com/vmm2136/besu_challenge/go-app/internal/handler"
 	"github.com/vmm2136/besu_challenge/go-app/internal/router"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/vmm2136/besu_challenge/go-app/internal/config"
 	"github.com/vmm2136/besu_challenge/go-app/internal/contract"
 	"github.com/vmm2136/besu_challenge/go-app/internal/database"
-	"github.com/vmm2136/besu_challenge/go-app/internal/pkg/ethutils"
 	"github.com/vmm2136/besu_challenge/go-app/internal/service"
 )
 
@@ -24,52 +29,223 @@ func main() {
 		os.Exit(1)
 	}
 
-	privateKey, err := ethutils.LoadPrivateKeyFromEnv("BESU_TRANSACTOR_PRIVATE_KEY")
+	// Subcomando "migrate": executa apenas as migrações do schema e encerra
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		dbClient, err := database.NewSQLDBClient(cfg.DatabaseURL)
+		if err != nil {
+			fmt.Printf("Erro ao inicializar cliente de banco de dados: %v\n", err)
+			os.Exit(1)
+		}
+		if err := runMigrateCommand(context.Background(), dbClient, os.Args[2:]); err != nil {
+			fmt.Printf("Erro ao executar migrações: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 1. Inicializar a camada de Banco de Dados (interage com o DB SQL) e aplicar as migrações pendentes
+	// Lembre-se de instalar o driver Go para o seu DB (ex: github.com/go-sql-driver/mysql)
+	dbClient, err := database.NewSQLDBClient(cfg.DatabaseURL)
 	if err != nil {
-		fmt.Printf("Erro ao carregar chave privada: %v\n", err)
+		fmt.Printf("Erro ao inicializar cliente de banco de dados: %v\n", err)
 		os.Exit(1)
 	}
 
-	pubAddress, err := ethutils.GetPublicKeyAddress(privateKey)
+	if cfg.DBAutoMigrate {
+		if _, err := dbClient.MigrateUp(context.Background()); err != nil {
+			fmt.Printf("Erro ao aplicar migrações do banco de dados: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// 2. Inicializar a camada de Contrato (carrega todos os contratos implantados pelo Ignition).
+	// As transações assinadas são gravadas no journal do DB antes do envio.
+	txTracker, err := contract.NewTxTracker(cfg.TxConfirmations, cfg.TxDropTimeout, cfg.TxPollInterval, service.NewTxJournal(dbClient))
+	if err != nil {
+		fmt.Printf("Erro ao inicializar acompanhamento de transações: %v\n", err)
+		os.Exit(1)
+	}
+	feeConfig := contract.FeeConfig{
+		Strategy:      cfg.FeeStrategy,
+		FixedGasPrice: cfg.FeeFixedGasPrice,
+		FixedTipCap:   cfg.FeeFixedTipCap,
+		Percentile:    cfg.FeePercentile,
+		HistoryBlocks: cfg.FeeHistoryBlocks,
+		MaxFeeCap:     cfg.FeeMaxFeeCap,
+		MaxTipCap:     cfg.FeeMaxTipCap,
+		PriceBump:     cfg.TxPriceBumpPercent,
+	}
+	gasConfig := contract.GasConfig{Multiplier: cfg.GasLimitMultiplier, Ceiling: cfg.GasLimitCeiling}
+	// Leituras são balanceadas entre os nós saudáveis de BESU_NODE_URLS; escritas vão ao primeiro saudável.
+	// Novos blocos e eventos chegam por assinatura nos endpoints ws:// ou IPC da lista.
+	poolConfig := contract.RPCPoolConfig{
+		URLs:                cfg.BesuNodeURLs,
+		HealthInterval:      cfg.RPCHealthInterval,
+		MaxBlockLag:         cfg.RPCMaxBlockLag,
+		MinPeers:            cfg.RPCMinPeers,
+		HeadPollInterval:    cfg.RPCHeadPollInterval,
+		ReconnectMaxBackoff: cfg.RPCReconnectBackoff,
+	}
+	contractRegistry, err := contract.NewRegistry(poolConfig, cfg.ContractAddressesPath, cfg.ContractArtifactsDir, txTracker, feeConfig, gasConfig)
 	if err != nil {
-		fmt.Printf("Erro ao obter endereço público: %v\n", err)
+		fmt.Printf("Erro ao carregar contratos: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Printf("Transator usando o endereço: %s\n", pubAddress.Hex())
+	defer contractRegistry.Close()
+
+	// Subcomando "backfill": reconstrói o histórico a partir dos eventos da rede e encerra
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := runBackfillCommand(ctx, cfg, contractRegistry, dbClient, os.Args[2:]); err != nil {
+			fmt.Printf("Erro ao executar backfill: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	// 1. Inicializar a camada de Contrato (interage com a blockchain)
-	contractClient, err := contract.NewSmartContract(cfg.BesuNodeURL, cfg.ContractABIPath, cfg.ContractAddressesPath)
+	signers, closeSigners, err := loadSigners(context.Background(), cfg)
 	if err != nil {
-		fmt.Printf("Erro ao inicializar SmartContract: %v\n", err)
+		fmt.Printf("Erro ao carregar assinadores (%s): %v\n", cfg.SignerType, err)
 		os.Exit(1)
 	}
+	defer closeSigners()
 
-	// 2. Inicializar a camada de Banco de Dados (interage com o DB SQL)
-	// Lembre-se de instalar o driver Go para o seu DB (ex: github.com/go-sql-driver/mysql)
-	dbClient, err := database.NewSQLDBClient(cfg.DatabaseURL)
+	// As escritas são distribuídas entre as contas do pool, cada uma com a sua sequência de nonces
+	transactors, err := contractRegistry.NewTransactorPool(signers, contract.TransactorPoolConfig{
+		Strategy:   cfg.TransactorStrategy,
+		MinBalance: cfg.TransactorMinBalance,
+		BalanceTTL: cfg.TransactorBalanceTTL,
+	})
 	if err != nil {
-		fmt.Printf("Erro ao inicializar cliente de banco de dados: %v\n", err)
+		fmt.Printf("Erro ao criar pool de transatores: %v\n", err)
 		os.Exit(1)
 	}
+	for _, signer := range signers {
+		fmt.Printf("Transator usando o endereço: %s (assinador %s, estratégia %s)\n", signer.Address().Hex(), cfg.SignerType, cfg.TransactorStrategy)
+	}
 
-	// 3. Inicializar a camada de Serviço (contém a lógica de negócio, incluindo SYNC)
-	// Agora ele recebe tanto o client do contrato quanto o client do DB.
-	contractService, err := service.NewContractService(contractClient, dbClient, privateKey)
+	// Reconciliar com o nó as transações que ficaram abertas antes do último encerramento
+	report, err := contractRegistry.RecoverTransactions(context.Background())
 	if err != nil {
-		fmt.Printf("Erro ao inicializar ContractService: %v\n", err)
+		fmt.Printf("Erro ao recuperar transações pendentes: %v\n", err)
 		os.Exit(1)
 	}
+	if report.Open > 0 {
+		fmt.Printf("Recuperação de transações: %d abertas, %d reconciliadas, %d reenviadas, %d descartadas, %d com erro\n",
+			report.Open, report.Reconciled, report.Rebroadcasted, report.Dropped, report.Failed)
+	}
 
-	// 4. Inicializar a camada de Handler (expõe endpoints HTTP)
-	h := handler.NewHandler(contractService)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// 5. Configurar o Router (mapeia URLs para handlers)
-	router := router.NewRouter(h)
+	// 3. Para cada contrato: camada de Serviço, worker de sincronização e indexador de eventos
+	serviceRegistry := service.NewRegistry(cfg.DefaultContract)
+	var syncWorkers []*service.SyncWorker
+	var indexers []*service.Indexer
+	for _, name := range contractRegistry.Names() {
+		contractClient, _ := contractRegistry.Get(name)
+
+		contractService, err := service.NewContractService(contractClient, dbClient, transactors, cfg.SyncConfirmations)
+		if err != nil {
+			fmt.Printf("Erro ao inicializar ContractService de %s: %v\n", name, err)
+			os.Exit(1)
+		}
+
+		// 4. Worker de sincronização em segundo plano (mantém o DB atualizado com a rede)
+		var syncWorker *service.SyncWorker
+		if cfg.SyncEnabled && contractService.SupportsValue() {
+			syncWorker, err = service.NewSyncWorker(contractService, cfg.SyncMode, cfg.SyncInterval, cfg.SyncMaxBackoff)
+			if err != nil {
+				fmt.Printf("Erro ao inicializar worker de sincronização de %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			syncWorker.Start(ctx)
+			syncWorkers = append(syncWorkers, syncWorker)
+		}
 
-	// 6. Iniciar o Servidor HTTP
-	fmt.Printf("Servidor iniciado na porta :%s\n", cfg.ServerPort)
-	if err := http.ListenAndServe(":"+cfg.ServerPort, router); err != nil {
-		fmt.Printf("Erro ao iniciar servidor: %v\n", err)
+		// 5. Indexador de eventos (grava o histórico de alterações do contrato)
+		if cfg.IndexerEnabled && contractClient.SupportsValueChanged() {
+			indexer, err := service.NewIndexer(contractClient, dbClient, cfg.IndexerStartBlock, cfg.IndexerBatchSize, cfg.SyncConfirmations, cfg.IndexerInterval)
+			if err != nil {
+				fmt.Printf("Erro ao inicializar indexador de eventos de %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			indexer.Start(ctx)
+			indexers = append(indexers, indexer)
+		}
+
+		if err := serviceRegistry.Register(contractService, syncWorker); err != nil {
+			fmt.Printf("Erro ao registrar contrato %s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
+
+	if _, ok := serviceRegistry.Default(); !ok {
+		fmt.Printf("Aviso: contrato padrão %s não encontrado; as rotas sem /contracts/{name} responderão 404\n", cfg.DefaultContract)
+	}
+
+	// 6. Inicializar a camada de Handler (expõe endpoints HTTP). POST /value aceita o cabeçalho Idempotency-Key.
+	idempotencyService, err := service.NewIdempotencyService(dbClient, cfg.IdempotencyKeyTTL, cfg.IdempotencyStaleAfter)
+	if err != nil {
+		fmt.Printf("Erro ao inicializar serviço de idempotência: %v\n", err)
+		os.Exit(1)
+	}
+	// POST /tx/raw recebe transações assinadas pelos clientes, limitadas às funções de RAW_TX_ALLOWED_METHODS
+	rawTxService, err := service.NewRawTxService(serviceRegistry, transactors, cfg.RawTxAllowedMethods)
+	if err != nil {
+		fmt.Printf("Erro ao inicializar serviço de transações assinadas: %v\n", err)
+		os.Exit(1)
+	}
+	// POST /sign/* assina mensagens com as contas do pool (SIGNING_ENABLED, protegido por SIGNING_API_TOKEN);
+	// POST /verify/* recupera quem assinou
+	signatureService, err := service.NewSignatureService(transactors, cfg.SigningEnabled, cfg.SigningAPIToken)
+	if err != nil {
+		fmt.Printf("Erro ao inicializar serviço de assinaturas: %v\n", err)
 		os.Exit(1)
 	}
+	h := handler.NewHandler(serviceRegistry, idempotencyService, rawTxService, signatureService)
+
+	// 7. Configurar o Router (mapeia URLs para handlers)
+	router := router.NewRouter(h)
+
+	// 8. Iniciar o Servidor HTTP
+	// as requisições herdam serverCtx, cancelado se ainda houver alguma aberta ao fim do prazo de encerramento
+	serverCtx, cancelServer := context.WithCancel(context.Background())
+	defer cancelServer()
+	server := &http.Server{
+		Addr:        ":" + cfg.ServerPort,
+		Handler:     router,
+		BaseContext: func(net.Listener) context.Context { return serverCtx },
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		fmt.Printf("Servidor iniciado na porta :%s\n", cfg.ServerPort)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("Erro ao iniciar servidor: %v\n", err)
+			os.Exit(1)
+		}
+	case <-ctx.Done():
+		fmt.Println("Encerrando aplicação...")
+	}
+
+	// 9. Encerrar de forma ordenada: para de aceitar requisições e aguarda os workers terminarem
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		fmt.Printf("Erro ao encerrar servidor: %v\n", err)
+		cancelServer()
+		server.Close()
+	}
+	for _, syncWorker := range syncWorkers {
+		syncWorker.Stop()
+	}
+	for _, indexer := range indexers {
+		indexer.Stop()
+	}
 }
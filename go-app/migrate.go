@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/vmm2136/besu_challenge/go-app/internal/database"
+)
+
+// runMigrateCommand executa o subcomando "migrate": up (padrão), down [n] ou status
+func runMigrateCommand(ctx context.Context, dbClient *database.SQLDBClient, args []string) error {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "up":
+		applied, err := dbClient.MigrateUp(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migração(ões) aplicada(s).\n", len(applied))
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("número de migrações a reverter inválido: %s", args[1])
+			}
+			steps = n
+		}
+		reverted, err := dbClient.MigrateDown(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migração(ões) revertida(s).\n", len(reverted))
+		return nil
+
+	case "status":
+		states, err := dbClient.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			status := "pendente"
+			if state.Applied {
+				status = "aplicada em " + state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d %-40s %s\n", state.Version, state.Name, status)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("ação de migração desconhecida '%s' (use up, down [n] ou status)", action)
+	}
+}
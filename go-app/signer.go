@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/vmm2136/besu_challenge/go-app/internal/config"
+	"github.com/vmm2136/besu_challenge/go-app/internal/pkg/ethutils"
+)
+
+// Tipos de assinador aceitos em SIGNER_TYPE
+const (
+	SignerTypeEnv      = "env"      // chaves em hexadecimal em BESU_TRANSACTOR_PRIVATE_KEY(S)
+	SignerTypeKeystore = "keystore" // keystores V3 cifrados com senha em arquivo
+	SignerTypeMnemonic = "mnemonic" // mnemônico BIP-39 com caminho de derivação BIP-44
+	SignerTypeRemote   = "remote"   // assinador externo via JSON-RPC eth_signTransaction
+)
+
+// loadSigners cria os assinadores das contas transatoras conforme SIGNER_TYPE. A função retornada libera
+// os recursos dos assinadores (a conexão com o assinador externo) e deve ser chamada no encerramento.
+func loadSigners(ctx context.Context, cfg *config.Config) ([]ethutils.Signer, func(), error) {
+	noop := func() {}
+	var signers []ethutils.Signer
+
+	switch cfg.SignerType {
+	case SignerTypeEnv:
+		keys, err := ethutils.LoadPrivateKeysFromEnv("BESU_TRANSACTOR_PRIVATE_KEY")
+		if err != nil {
+			return nil, noop, err
+		}
+		extraKeys, err := ethutils.LoadPrivateKeysFromEnv("BESU_TRANSACTOR_PRIVATE_KEYS")
+		if err != nil {
+			return nil, noop, err
+		}
+		keys = append(keys, extraKeys...)
+		if len(keys) == 0 {
+			return nil, noop, fmt.Errorf("BESU_TRANSACTOR_PRIVATE_KEY ou BESU_TRANSACTOR_PRIVATE_KEYS é obrigatório com SIGNER_TYPE=%s", SignerTypeEnv)
+		}
+		for _, key := range keys {
+			signer, err := ethutils.NewPrivateKeySigner(key)
+			if err != nil {
+				return nil, noop, err
+			}
+			signers = append(signers, signer)
+		}
+	case SignerTypeKeystore:
+		if len(cfg.SignerKeystorePaths) == 0 {
+			return nil, noop, fmt.Errorf("SIGNER_KEYSTORE_PATH é obrigatório com SIGNER_TYPE=%s", SignerTypeKeystore)
+		}
+		for _, path := range cfg.SignerKeystorePaths {
+			signer, err := ethutils.LoadKeystoreSigner(path, cfg.SignerPassphraseFile)
+			if err != nil {
+				return nil, noop, err
+			}
+			signers = append(signers, signer)
+		}
+	case SignerTypeMnemonic:
+		if cfg.SignerMnemonicFile == "" {
+			return nil, noop, fmt.Errorf("SIGNER_MNEMONIC_FILE é obrigatório com SIGNER_TYPE=%s", SignerTypeMnemonic)
+		}
+		derived, err := ethutils.LoadMnemonicSigners(cfg.SignerMnemonicFile, cfg.SignerPassphraseFile, cfg.SignerDerivationPath, int(cfg.SignerAccountCount))
+		if err != nil {
+			return nil, noop, err
+		}
+		for _, signer := range derived {
+			signers = append(signers, signer)
+		}
+	case SignerTypeRemote:
+		remote, err := ethutils.DialRemoteSigner(ctx, cfg.SignerRemoteURL)
+		if err != nil {
+			return nil, noop, err
+		}
+		addresses, err := remoteSignerAddresses(ctx, remote, cfg.SignerRemoteAddresses)
+		if err != nil {
+			remote.Close()
+			return nil, noop, err
+		}
+		for _, address := range addresses {
+			signers = append(signers, remote.Signer(address))
+		}
+		return signers, remote.Close, nil
+	default:
+		return nil, noop, fmt.Errorf("SIGNER_TYPE inválido: %s (use %s, %s, %s ou %s)", cfg.SignerType,
+			SignerTypeEnv, SignerTypeKeystore, SignerTypeMnemonic, SignerTypeRemote)
+	}
+	return signers, noop, nil
+}
+
+// remoteSignerAddresses retorna as contas de SIGNER_REMOTE_ADDRESS ou, se vazio, todas as contas do assinador externo
+func remoteSignerAddresses(ctx context.Context, remote *ethutils.RemoteSignerClient, configured []string) ([]common.Address, error) {
+	if len(configured) == 0 {
+		addresses, err := remote.Accounts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(addresses) == 0 {
+			return nil, fmt.Errorf("assinador externo não possui contas")
+		}
+		return addresses, nil
+	}
+
+	addresses := make([]common.Address, 0, len(configured))
+	for _, address := range configured {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("SIGNER_REMOTE_ADDRESS inválido: %s", address)
+		}
+		addresses = append(addresses, common.HexToAddress(address))
+	}
+	return addresses, nil
+}